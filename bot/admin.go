@@ -1,19 +1,17 @@
 package bot
 
-import (
-	"log"
-
-	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
-)
-
-func (b *Bot) AdminBroadcast(msg string) {
-	users, err := storage.GetAllUsers(b.db)
+// AdminBroadcast отправляет сообщение всем активным пользователям.
+// Возвращает число успешно отправленных и всего получателей
+func (b *Bot) AdminBroadcast(msg string) (sent, total int, err error) {
+	users, err := b.store.Users.GetReachableUsers()
 	if err != nil {
-		log.Printf("Ошибка выборки пользователей: %v", err)
-		return
+		return 0, 0, err
 	}
 
 	for _, u := range users {
-		b.SendMessage(u, "📢 "+msg)
+		if _, err := b.send(u, "📢 "+msg); err == nil {
+			sent++
+		}
 	}
+	return sent, len(users), nil
 }
@@ -0,0 +1,166 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+	tb "gopkg.in/telebot.v3"
+)
+
+const (
+	// максимум активных алертов на пользователя
+	maxAlertsPerUser = 20
+	// за какой период прикладывать новости к сработавшему алерту
+	alertNewsWindow = 24 * time.Hour
+	alertNewsLimit  = 3
+)
+
+var alertRe = regexp.MustCompile(`^(.+?)\s*(>=|<=|>|<)\s*([0-9]+(?:[.,][0-9]+)?)$`)
+
+// parseAlert разбирает «SBER > 300»
+func (b *Bot) parseAlert(userID int64, s string) (storage.PriceAlert, bool) {
+	m := alertRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return storage.PriceAlert{}, false
+	}
+	ticker := b.resolveTicker(m[1])
+	level, err := strconv.ParseFloat(strings.Replace(m[3], ",", ".", 1), 64)
+	if ticker == "" || err != nil {
+		return storage.PriceAlert{}, false
+	}
+	return storage.PriceAlert{UserID: userID, Ticker: ticker, Op: m[2], Level: level}, true
+}
+
+func alertTriggered(a storage.PriceAlert, price float64) bool {
+	switch a.Op {
+	case ">":
+		return price > a.Level
+	case ">=":
+		return price >= a.Level
+	case "<":
+		return price < a.Level
+	case "<=":
+		return price <= a.Level
+	}
+	return false
+}
+
+func formatAlert(a storage.PriceAlert) string {
+	return fmt.Sprintf("%s %s %g", a.Ticker, a.Op, a.Level)
+}
+
+func (b *Bot) HandleAlert(userID int64, arg string) {
+	if b.quotes == nil {
+		b.SendMessage(userID, "⚠️ Котировки не настроены")
+		return
+	}
+	a, ok := b.parseAlert(userID, arg)
+	if !ok {
+		b.SendMessage(userID, "⚠️ Формат: /alert SBER > 300 (также <, >=, <=)")
+		return
+	}
+	existing, _ := b.store.Alerts.GetUserPriceAlerts(userID)
+	if len(existing) >= maxAlertsPerUser {
+		b.SendMessage(userID, fmt.Sprintf("⚠️ Максимум %d алертов", maxAlertsPerUser))
+		return
+	}
+	if _, err := b.store.Alerts.AddPriceAlert(a); err != nil {
+		b.SendMessage(userID, "❌ Ошибка сохранения алерта")
+		return
+	}
+
+	msg := "🔔 Алерт создан: " + formatAlert(a)
+	if q, err := b.quotes.Quote(a.Ticker); err == nil {
+		msg += "\nСейчас: " + q.String()
+	}
+	b.SendMessage(userID, msg)
+}
+
+// ShowAlerts показывает активные алерты с кнопками удаления
+func (b *Bot) ShowAlerts(userID int64, c tb.Context) {
+	alerts, _ := b.store.Alerts.GetUserPriceAlerts(userID)
+	text := "🔔 Активные алерты:\n\nНажмите на алерт, чтобы удалить его."
+	if len(alerts) == 0 {
+		text = "Активных алертов нет. Создать: /alert SBER > 300"
+	}
+
+	var rows [][]tb.InlineButton
+	for _, a := range alerts {
+		btn := b.btnAlertDel
+		btn.Text = "❌ " + formatAlert(a)
+		btn.Data = strconv.FormatInt(a.ID, 10)
+		rows = append(rows, []tb.InlineButton{btn})
+	}
+	markup := &tb.ReplyMarkup{InlineKeyboard: rows}
+
+	if c != nil {
+		_ = c.Edit(text, markup)
+	} else {
+		_, _ = b.bot.Send(tb.ChatID(userID), text, markup)
+	}
+}
+
+// StartAlertChecker раз в минуту проверяет алерты по текущим котировкам
+func (b *Bot) StartAlertChecker() {
+	ticker := time.NewTicker(time.Minute)
+	for range ticker.C {
+		if b.quotes == nil {
+			continue
+		}
+		b.checkAlerts()
+	}
+}
+
+func (b *Bot) checkAlerts() {
+	alerts, err := b.store.Alerts.GetActivePriceAlerts()
+	if err != nil {
+		log.Printf("Ошибка выборки алертов: %v", err)
+		return
+	}
+
+	// одна котировка на тикер за проход; NaN — котировку получить не удалось
+	prices := make(map[string]float64)
+	for _, a := range alerts {
+		price, ok := prices[a.Ticker]
+		if !ok {
+			price = math.NaN()
+			if q, err := b.quotes.Quote(a.Ticker); err == nil {
+				price = q.Price
+			}
+			prices[a.Ticker] = price
+		}
+
+		if math.IsNaN(price) || !alertTriggered(a, price) {
+			continue
+		}
+		if marked, err := b.store.Alerts.MarkPriceAlertTriggered(a.ID); err != nil || !marked {
+			continue
+		}
+		b.SendMessage(a.UserID, b.formatTriggeredAlert(a, price))
+	}
+}
+
+func (b *Bot) formatTriggeredAlert(a storage.PriceAlert, price float64) string {
+	msg := fmt.Sprintf("🚨 Сработал алерт %s\nЦена: %.2f", formatAlert(a), price)
+
+	news, _ := b.store.News.GetNewsByTag(a.Ticker, alertNewsLimit)
+	var recent []storage.NewsItem
+	for _, n := range news {
+		if time.Since(n.PubDate) <= alertNewsWindow {
+			recent = append(recent, n)
+		}
+	}
+	if len(recent) > 0 {
+		msg += "\n\n📰 Свежие новости:"
+		for _, n := range recent {
+			msg += fmt.Sprintf("\n• %s\n%s", n.Title, n.Link)
+		}
+	}
+	return msg
+}
@@ -0,0 +1,149 @@
+package bot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/quotes"
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+func TestParseAlert(t *testing.T) {
+	env := newTestEnv(t)
+	tests := []struct {
+		in     string
+		ticker string
+		op     string
+		level  float64
+		ok     bool
+	}{
+		{"SBER > 300", "SBER", ">", 300, true},
+		{"sber>=300,5", "SBER", ">=", 300.5, true},
+		{"  GAZP <150.25  ", "GAZP", "<", 150.25, true},
+		{"$LKOH <= 7000", "LKOH", "<=", 7000, true},
+		{"Сбербанк > 280", "SBER", ">", 280, true},
+		{"", "", "", 0, false},
+		{"SBER", "", "", 0, false},
+		{"SBER >", "", "", 0, false},
+		{"> 300", "", "", 0, false},
+		{"SBER = 300", "", "", 0, false},
+		{"SBER > -5", "", "", 0, false},
+		{"SBER > 1.2.3", "", "", 0, false},
+		{"SBER > abc", "", "", 0, false},
+		{"!!! > 300", "", "", 0, false},
+	}
+	for _, tt := range tests {
+		a, ok := env.bot.parseAlert(testUserID, tt.in)
+		if ok != tt.ok {
+			t.Errorf("parseAlert(%q): ok = %v, ожидалось %v", tt.in, ok, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		want := storage.PriceAlert{UserID: testUserID, Ticker: tt.ticker, Op: tt.op, Level: tt.level}
+		if a != want {
+			t.Errorf("parseAlert(%q) = %+v, ожидалось %+v", tt.in, a, want)
+		}
+	}
+}
+
+// writeQuotes записывает CSV для quotes.FileProvider; время изменения
+// сдвигается, чтобы провайдер перечитал файл даже в пределах одной секунды
+func writeQuotes(t *testing.T, path, csv string, mod time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(csv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAlertTriggered(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.csv")
+	writeQuotes(t, path, "# ticker,price\nSBER,300\nGAZP,150.5\n", time.Now())
+	provider, err := quotes.NewFileProvider(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ticker string
+		op     string
+		level  float64
+		want   bool
+	}{
+		{"SBER", ">", 299.99, true},
+		{"SBER", ">", 300, false},
+		{"SBER", ">=", 300, true},
+		{"SBER", ">=", 300.01, false},
+		{"SBER", "<", 300.01, true},
+		{"SBER", "<", 300, false},
+		{"SBER", "<=", 300, true},
+		{"SBER", "<=", 299.99, false},
+		{"GAZP", ">", 150, true},
+		{"GAZP", "<", 150, false},
+		{"SBER", "=", 300, false},
+	}
+	for _, tt := range tests {
+		q, err := provider.Quote(tt.ticker)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a := storage.PriceAlert{Ticker: tt.ticker, Op: tt.op, Level: tt.level}
+		if got := alertTriggered(a, q.Price); got != tt.want {
+			t.Errorf("%s при цене %g: %v, ожидалось %v", formatAlert(a), q.Price, got, tt.want)
+		}
+	}
+}
+
+// Алерт срабатывает один раз, когда цена из файла котировок пересекает уровень
+func TestCheckAlerts(t *testing.T) {
+	env := newTestEnv(t)
+	path := filepath.Join(t.TempDir(), "quotes.csv")
+	mod := time.Now().Add(-time.Hour)
+	writeQuotes(t, path, "SBER,300\nGAZP,150\n", mod)
+	provider, err := quotes.NewFileProvider(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.bot.SetQuoteProvider(provider)
+
+	for _, in := range []string{"SBER > 310", "GAZP < 140", "YDEX > 1"} {
+		env.send("/alert " + in)
+		if got := env.last("sendMessage").text(); !strings.Contains(got, "Алерт создан") {
+			t.Fatalf("/alert %s: %q", in, got)
+		}
+	}
+
+	// уровни не пересечены, по YDEX котировки нет
+	env.bot.checkAlerts()
+	if sent := env.flush(); sent != 0 {
+		t.Fatalf("алерты сработали раньше времени: %d сообщений", sent)
+	}
+
+	writeQuotes(t, path, "SBER,315\nGAZP,150\n", mod.Add(time.Minute))
+	env.bot.checkAlerts()
+	if got := env.last("sendMessage").text(); !strings.Contains(got, "Сработал алерт SBER > 310") || !strings.Contains(got, "315.00") {
+		t.Errorf("сообщение о срабатывании: %q", got)
+	}
+	active, _ := env.store.Alerts.GetUserPriceAlerts(testUserID)
+	if len(active) != 2 {
+		t.Errorf("после срабатывания активных алертов %d, ожидалось 2", len(active))
+	}
+
+	writeQuotes(t, path, "SBER,320\nGAZP,139.9\n", mod.Add(2*time.Minute))
+	env.bot.checkAlerts()
+	if got := env.last("sendMessage").text(); !strings.Contains(got, "Сработал алерт GAZP < 140") {
+		t.Errorf("сообщение о срабатывании: %q", got)
+	}
+	// SBER уже сработал и повторно не приходит
+	env.bot.checkAlerts()
+	if sent := env.flush(); sent != 0 {
+		t.Errorf("повторные уведомления: %d", sent)
+	}
+}
@@ -0,0 +1,191 @@
+package bot
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"html"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+	tb "gopkg.in/telebot.v3"
+)
+
+const (
+	// записей журнала на странице /audit
+	auditPageSize = 10
+	// максимум записей в CSV-выгрузке
+	auditExportLimit = 10000
+)
+
+const auditUsage = "⚠️ Формат: /audit [admin=<id>] [action=<действие>] [csv]"
+
+// resultOf — итог действия для журнала: ok или текст ошибки
+func resultOf(err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return "ok"
+}
+
+// audit записывает действие админа в журнал
+func (b *Bot) audit(actorID int64, action, args, result string) {
+	err := b.store.Audit.AddAuditEntry(storage.AuditEntry{
+		ActorID: actorID,
+		Action:  action,
+		Args:    args,
+		Result:  result,
+	})
+	if err != nil {
+		log.Printf("Ошибка записи в журнал (%d %s %s): %v", actorID, action, args, err)
+	}
+}
+
+// parseAuditFilter разбирает admin=<id> action=<действие> csv
+func parseAuditFilter(arg string) (f storage.AuditFilter, export bool, ok bool) {
+	for _, p := range strings.Fields(arg) {
+		key, value, _ := strings.Cut(p, "=")
+		switch strings.ToLower(key) {
+		case "admin":
+			id, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return f, false, false
+			}
+			f.ActorID = id
+		case "action":
+			if value == "" {
+				return f, false, false
+			}
+			f.Action = strings.TrimPrefix(value, "/")
+		case "csv":
+			export = true
+		default:
+			return f, false, false
+		}
+	}
+	return f, export, true
+}
+
+// HandleAudit — /audit для админов
+func (b *Bot) HandleAudit(userID int64, arg string) {
+	f, export, ok := parseAuditFilter(arg)
+	if !ok {
+		b.SendMessage(userID, auditUsage)
+		return
+	}
+	b.auditFilter[userID] = f
+	if export {
+		b.SendAuditCSV(userID)
+		return
+	}
+	b.auditPage[userID] = 1
+	b.ShowAuditLog(userID, nil)
+}
+
+// ShowAuditLog показывает текущую страницу журнала по фильтру пользователя
+func (b *Bot) ShowAuditLog(chatID int64, c tb.Context) {
+	f := b.auditFilter[chatID]
+	total, err := b.store.Audit.CountAuditLog(f)
+	if err != nil {
+		log.Printf("Ошибка чтения журнала: %v", err)
+	}
+	if total == 0 {
+		if c != nil {
+			_ = c.Edit("Журнал пуст.")
+		} else {
+			b.SendMessage(chatID, "Журнал пуст.")
+		}
+		return
+	}
+
+	totalPages := (total + auditPageSize - 1) / auditPageSize
+	page := b.auditPage[chatID]
+	if page < 1 {
+		page = 1
+	}
+	if page > totalPages {
+		page = totalPages
+	}
+	b.auditPage[chatID] = page
+	entries, _ := b.store.Audit.GetAuditLog(f, page, auditPageSize)
+
+	text := "📜 Журнал действий"
+	if f.ActorID != 0 {
+		text += fmt.Sprintf(", админ %d", f.ActorID)
+	}
+	if f.Action != "" {
+		text += ", " + html.EscapeString(f.Action)
+	}
+	text += ":\n"
+	for _, e := range entries {
+		text += fmt.Sprintf("\n<b>%s</b> %s · %d\n", html.EscapeString(e.Action), e.CreatedAt.In(moscow).Format("02.01 15:04"), e.ActorID)
+		if e.Args != "" {
+			text += html.EscapeString(truncate(e.Args, 200)) + "\n"
+		}
+		mark := "✅"
+		if e.Result != "ok" {
+			mark = "❌"
+		}
+		text += mark + " " + html.EscapeString(truncate(e.Result, 200)) + "\n"
+	}
+	text += fmt.Sprintf("\n📄 Страница %d/%d, записей: %d", page, totalPages, total)
+
+	var rows [][]tb.InlineButton
+	if row := navRow(page, totalPages, b.btnAuditFirst, b.btnAuditPrev, b.btnAuditNext, b.btnAuditLast); len(row) > 0 {
+		rows = append(rows, row)
+	}
+	rows = append(rows, []tb.InlineButton{b.btnAuditCSV})
+
+	opts := &tb.SendOptions{ParseMode: tb.ModeHTML, ReplyMarkup: &tb.ReplyMarkup{InlineKeyboard: rows}, DisableWebPagePreview: true}
+	if c != nil {
+		_ = c.Edit(text, opts)
+	} else {
+		_, _ = b.bot.Send(tb.ChatID(chatID), text, opts)
+	}
+}
+
+// SendAuditCSV выгружает журнал по фильтру пользователя файлом
+func (b *Bot) SendAuditCSV(chatID int64) {
+	entries, err := b.store.Audit.GetAuditLog(b.auditFilter[chatID], 1, auditExportLimit)
+	if err != nil {
+		log.Printf("Ошибка чтения журнала: %v", err)
+		b.SendMessage(chatID, "❌ Ошибка чтения журнала")
+		return
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"id", "created_at", "actor_id", "action", "args", "result"})
+	for _, e := range entries {
+		_ = w.Write([]string{
+			strconv.FormatInt(e.ID, 10),
+			e.CreatedAt.UTC().Format(time.RFC3339),
+			strconv.FormatInt(e.ActorID, 10),
+			e.Action,
+			e.Args,
+			e.Result,
+		})
+	}
+	w.Flush()
+
+	doc := &tb.Document{
+		File:     tb.FromReader(&buf),
+		FileName: "audit-" + time.Now().In(moscow).Format("20060102-1504") + ".csv",
+		Caption:  fmt.Sprintf("📜 Записей: %d", len(entries)),
+	}
+	if _, err := b.bot.Send(tb.ChatID(chatID), doc); err != nil {
+		log.Printf("Ошибка отправки журнала: %v", err)
+	}
+}
+
+// truncate обрезает строку до n символов
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "…"
+}
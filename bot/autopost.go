@@ -2,26 +2,325 @@ package bot
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
 	tb "gopkg.in/telebot.v3"
 )
 
-func (b *Bot) ShowAutopostMenu(chatID int64) {
-	menu := &tb.ReplyMarkup{}
-	var rows [][]tb.InlineButton
+// Максимум времён дайджеста у пользователя
+const maxAutopostTimes = 6
+
+var clockRe = regexp.MustCompile(`^([01]?[0-9]|2[0-3]):([0-5][0-9])$`)
+
+// Названия дней для /autopost: пн/ср/пт, mon,wed,fri
+var weekdayNames = map[string]time.Weekday{
+	"пн": time.Monday, "вт": time.Tuesday, "ср": time.Wednesday, "чт": time.Thursday,
+	"пт": time.Friday, "сб": time.Saturday, "вс": time.Sunday,
+	"mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday, "thu": time.Thursday,
+	"fri": time.Friday, "sat": time.Saturday, "sun": time.Sunday,
+}
+
+// Наборы дней в меню /autopost
+var autopostDayPresets = []struct {
+	Text string
+	Days storage.Weekdays
+}{
+	{"Ежедневно", storage.EveryDay},
+	{"Будни", storage.WorkingDays},
+	{"Пн/Ср/Пт", storage.WeekdaysOf(time.Monday, time.Wednesday, time.Friday)},
+	{"Выходные", storage.WeekendDays},
+}
+
+const autopostUsage = "⚠️ Формат: /autopost 09:00 18:30 [будни|выходные|ежедневно|пн/ср/пт]\n" +
+	"/autopost off – приостановить, /autopost on – возобновить"
+
+// parseClock разбирает HH:MM в минуты от полуночи
+func parseClock(s string) (int, bool) {
+	m := clockRe.FindStringSubmatch(s)
+	if m == nil {
+		return 0, false
+	}
+	h, _ := strconv.Atoi(m[1])
+	min, _ := strconv.Atoi(m[2])
+	return h*60 + min, true
+}
+
+// parseWeekdays разбирает «будни», «выходные», «ежедневно» или список дней через / или запятую.
+// Повтор дня в списке — скорее опечатка, такой список не принимается
+func parseWeekdays(s string) (storage.Weekdays, bool) {
+	switch strings.ToLower(s) {
+	case "ежедневно", "daily":
+		return storage.EveryDay, true
+	case "будни", "weekdays":
+		return storage.WorkingDays, true
+	case "выходные", "weekends":
+		return storage.WeekendDays, true
+	}
+	var days storage.Weekdays
+	for _, name := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool { return r == '/' || r == ',' }) {
+		d, ok := weekdayNames[name]
+		if !ok || days.Has(d) {
+			return 0, false
+		}
+		days |= storage.WeekdaysOf(d)
+	}
+	return days, days != 0
+}
+
+// formatWeekdays описывает набор дней для сообщений
+func formatWeekdays(days storage.Weekdays) string {
+	switch days {
+	case storage.EveryDay:
+		return "ежедневно"
+	case storage.WorkingDays:
+		return "по будням"
+	case storage.WeekendDays:
+		return "по выходным"
+	}
+	var names []string
+	// неделя с понедельника
+	for i := 1; i <= 7; i++ {
+		if d := time.Weekday(i % 7); days.Has(d) {
+			names = append(names, weekdays[d])
+		}
+	}
+	return strings.Join(names, "/")
+}
+
+// formatAutopost описывает расписание одной строкой
+func formatAutopost(schedules []storage.AutopostSchedule) string {
+	if len(schedules) == 0 {
+		return "не задано"
+	}
+	var parts []string
+	enabled := false
+	for _, s := range schedules {
+		parts = append(parts, s.Clock()+" "+formatWeekdays(s.Weekdays))
+		enabled = enabled || s.Enabled
+	}
+	text := strings.Join(parts, ", ") + " (" + schedules[0].Timezone + ")"
+	if !enabled {
+		text += ", приостановлено"
+	}
+	return text
+}
+
+// HandleAutopost разбирает /autopost 09:00 18:30 будни
+func (b *Bot) HandleAutopost(userID int64, arg string) {
+	schedules, _ := b.store.Autopost.GetUserAutopost(userID)
+	switch cmd := strings.ToLower(arg); cmd {
+	case "off", "выкл", "on", "вкл":
+		if len(schedules) == 0 {
+			b.SendMessage(userID, "⚠️ Время дайджеста не задано")
+			return
+		}
+		enabled := cmd == "on" || cmd == "вкл"
+		for i := range schedules {
+			schedules[i].Enabled = enabled
+		}
+		b.saveAutopost(userID, schedules)
+		return
+	}
+
+	var times []int
+	var days storage.Weekdays
+	for _, p := range strings.Fields(arg) {
+		if t, ok := parseClock(p); ok {
+			times = append(times, t)
+		} else if d, ok := parseWeekdays(p); ok {
+			days |= d
+		} else {
+			b.SendMessage(userID, "⚠️ Не понял «"+p+"»\n"+autopostUsage)
+			return
+		}
+	}
+	if len(times) == 0 {
+		b.SendMessage(userID, autopostUsage)
+		return
+	}
+	if len(times) > maxAutopostTimes {
+		b.SendMessage(userID, fmt.Sprintf("⚠️ Максимум %d времён", maxAutopostTimes))
+		return
+	}
+	if days == 0 {
+		days = storage.EveryDay
+	}
+
+	tz := b.userLocation(userID).String()
+	schedules = schedules[:0]
+	seen := make(map[int]bool)
+	for _, t := range times {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		schedules = append(schedules, storage.AutopostSchedule{TimeOfDay: t, Weekdays: days, Timezone: tz, Enabled: true})
+	}
+	b.autopostDays[userID] = days
+	b.saveAutopost(userID, schedules)
+}
+
+// saveAutopost сохраняет расписание и сообщает результат
+func (b *Bot) saveAutopost(userID int64, schedules []storage.AutopostSchedule) {
+	if err := b.store.Autopost.SetUserAutopost(userID, schedules); err != nil {
+		b.SendMessage(userID, "❌ Ошибка сохранения расписания: "+err.Error())
+		return
+	}
+	saved, _ := b.store.Autopost.GetUserAutopost(userID)
+	b.SendMessage(userID, "✅ Дайджест: "+formatAutopost(saved))
+}
+
+// pickerDays — набор дней, выбранный в меню; по умолчанию — как у текущего расписания
+func (b *Bot) pickerDays(userID int64, schedules []storage.AutopostSchedule) storage.Weekdays {
+	if days, ok := b.autopostDays[userID]; ok {
+		return days
+	}
+	if len(schedules) > 0 {
+		return schedules[0].Weekdays
+	}
+	return storage.EveryDay
+}
+
+// ShowAutopostMenu показывает выбор часов и дней дайджеста.
+// c != nil — обновление меню после нажатия кнопки
+func (b *Bot) ShowAutopostMenu(chatID int64, c tb.Context) {
+	schedules, _ := b.store.Autopost.GetUserAutopost(chatID)
+	days := b.pickerDays(chatID, schedules)
+
+	selected := make(map[int]bool)
+	enabled := len(schedules) == 0
+	for _, s := range schedules {
+		selected[s.TimeOfDay] = true
+		enabled = enabled || s.Enabled
+	}
 
+	var rows [][]tb.InlineButton
+	var row []tb.InlineButton
 	for hour := 0; hour < 24; hour++ {
-		btn := tb.InlineButton{
-			Unique: fmt.Sprintf("ap_%02d00", hour),
-			Text:   fmt.Sprintf("%02d:00", hour),
+		btn := b.btnAutopostHour
+		btn.Text = fmt.Sprintf("%02d:00", hour)
+		if selected[hour*60] {
+			btn.Text = "✅ " + btn.Text
+		}
+		btn.Data = strconv.Itoa(hour)
+		row = append(row, btn)
+		if len(row) == 4 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+
+	var presets []tb.InlineButton
+	for _, p := range autopostDayPresets {
+		btn := b.btnAutopostDays
+		btn.Text = p.Text
+		if p.Days == days {
+			btn.Text = "✅ " + btn.Text
 		}
-		rows = append(rows, []tb.InlineButton{btn})
+		btn.Data = strconv.Itoa(int(p.Days))
+		presets = append(presets, btn)
 	}
+	rows = append(rows, presets)
 
-	menu.InlineKeyboard = rows
+	if len(schedules) > 0 {
+		toggle := b.btnAutopostToggle
+		toggle.Text = "⏸ Приостановить"
+		if !enabled {
+			toggle.Text = "▶️ Возобновить"
+		}
+		rows = append(rows, []tb.InlineButton{toggle})
+	}
 
-	_, _ = b.bot.Send(
-		tb.ChatID(chatID),
-		"Выберите время авторассылки (по Москве):",
-		menu,
-	)
+	text := fmt.Sprintf("🕘 Время дайджеста (%s)\nСейчас: %s\n\n"+
+		"Нажмите на час, чтобы добавить или убрать его, и выберите дни.\n"+
+		"Точное время: /autopost 09:30 18:00 пн/ср/пт",
+		b.userLocation(chatID), formatAutopost(schedules))
+	markup := &tb.ReplyMarkup{InlineKeyboard: rows}
+	if c != nil {
+		_ = c.Edit(text, markup)
+	} else {
+		_, _ = b.bot.Send(tb.ChatID(chatID), text, markup)
+	}
+}
+
+// toggleAutopostHour добавляет или убирает час в расписании (кнопка меню)
+func (b *Bot) toggleAutopostHour(c tb.Context) error {
+	userID := c.Sender().ID
+	hour, err := strconv.Atoi(c.Data())
+	if err != nil {
+		return c.Respond()
+	}
+	schedules, _ := b.store.Autopost.GetUserAutopost(userID)
+
+	kept := schedules[:0]
+	removed := false
+	for _, s := range schedules {
+		if s.TimeOfDay == hour*60 {
+			removed = true
+			continue
+		}
+		kept = append(kept, s)
+	}
+	if !removed {
+		if len(kept) >= maxAutopostTimes {
+			return c.Respond(&tb.CallbackResponse{Text: fmt.Sprintf("Максимум %d времён", maxAutopostTimes)})
+		}
+		enabled := true
+		if len(kept) > 0 {
+			enabled = kept[0].Enabled
+		}
+		kept = append(kept, storage.AutopostSchedule{
+			TimeOfDay: hour * 60,
+			Weekdays:  b.pickerDays(userID, schedules),
+			Timezone:  b.userLocation(userID).String(),
+			Enabled:   enabled,
+		})
+	}
+	if err := b.store.Autopost.SetUserAutopost(userID, kept); err != nil {
+		return c.Respond(&tb.CallbackResponse{Text: "❌ Ошибка сохранения"})
+	}
+	b.ShowAutopostMenu(userID, c)
+	return c.Respond()
+}
+
+// setAutopostDays задаёт дни для всего расписания (кнопка меню)
+func (b *Bot) setAutopostDays(c tb.Context) error {
+	userID := c.Sender().ID
+	mask, err := strconv.Atoi(c.Data())
+	if err != nil {
+		return c.Respond()
+	}
+	days := storage.Weekdays(mask)
+	schedules, _ := b.store.Autopost.GetUserAutopost(userID)
+	for i := range schedules {
+		schedules[i].Weekdays = days
+	}
+	if err := b.store.Autopost.SetUserAutopost(userID, schedules); err != nil {
+		return c.Respond(&tb.CallbackResponse{Text: "❌ Ошибка сохранения"})
+	}
+	b.autopostDays[userID] = days
+	b.ShowAutopostMenu(userID, c)
+	return c.Respond()
+}
+
+// toggleAutopost приостанавливает или возобновляет дайджест (кнопка меню)
+func (b *Bot) toggleAutopost(c tb.Context) error {
+	userID := c.Sender().ID
+	schedules, _ := b.store.Autopost.GetUserAutopost(userID)
+	enabled := false
+	for _, s := range schedules {
+		enabled = enabled || s.Enabled
+	}
+	for i := range schedules {
+		schedules[i].Enabled = !enabled
+	}
+	if err := b.store.Autopost.SetUserAutopost(userID, schedules); err != nil {
+		return c.Respond(&tb.CallbackResponse{Text: "❌ Ошибка сохранения"})
+	}
+	b.ShowAutopostMenu(userID, c)
+	return c.Respond()
 }
@@ -0,0 +1,66 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{"09:00", 9 * 60, true},
+		{"9:05", 9*60 + 5, true},
+		{"00:00", 0, true},
+		{"23:59", 23*60 + 59, true},
+		{"18:30", 18*60 + 30, true},
+		{"ab:cd", 0, false},
+		{"9:5", 0, false},
+		{"24:00", 0, false},
+		{"12:60", 0, false},
+		{"", 0, false},
+		{"0900", 0, false},
+		{"09:00:00", 0, false},
+		{"-1:30", 0, false},
+		{"123:00", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseClock(tt.in)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseClock(%q) = %d, %v; ожидалось %d, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseWeekdays(t *testing.T) {
+	tests := []struct {
+		in   string
+		want storage.Weekdays
+		ok   bool
+	}{
+		{"ежедневно", storage.EveryDay, true},
+		{"Будни", storage.WorkingDays, true},
+		{"weekends", storage.WeekendDays, true},
+		{"пн/ср/пт", storage.WeekdaysOf(time.Monday, time.Wednesday, time.Friday), true},
+		{"Mon,Wed,Fri", storage.WeekdaysOf(time.Monday, time.Wednesday, time.Friday), true},
+		{"сб/sun", storage.WeekendDays, true},
+		{"вс", storage.WeekdaysOf(time.Sunday), true},
+		{"", 0, false},
+		{"/", 0, false},
+		{"пн/xx", 0, false},
+		{"понедельник", 0, false},
+		{"пн/пн", 0, false},
+		{"mon,пн", 0, false},
+		{"пн ср", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseWeekdays(tt.in)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseWeekdays(%q) = %07b, %v; ожидалось %07b, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
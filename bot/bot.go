@@ -1,29 +1,86 @@
 package bot
 
 import (
-	"database/sql"
-	"fmt"
 	"log"
+	"math"
+	"strconv"
 	"time"
 
+	"github.com/FFFFFFFFFFj/trade-news-bot/ingest"
+	"github.com/FFFFFFFFFFj/trade-news-bot/quotes"
 	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
 	tb "gopkg.in/telebot.v3"
 )
 
 type Bot struct {
-	bot        *tb.Bot
-	db         *sql.DB
-	pending    map[int64]string
-	latestPage map[int64]int
+	bot     *tb.Bot
+	store   *storage.Store
+	pending map[int64]string
+	// закэшированное число новостей для счётчика страниц /latest
+	latestTotals map[int64]latestTotal
+	// показывать ли саммари в /latest
+	latestExpanded map[int64]bool
+	// источник котировок, nil — не настроен
+	quotes quotes.QuoteProvider
 
 	// кнопки навигации /latest
 	btnFirst tb.InlineButton
 	btnPrev  tb.InlineButton
 	btnNext  tb.InlineButton
 	btnLast  tb.InlineButton
+	btnMore  tb.InlineButton
+	btnLess  tb.InlineButton
+
+	// последний запрос /search и текущая страница результатов
+	searchQuery map[int64]storage.SearchQuery
+	searchPage  map[int64]int
+	// кнопки навигации /search
+	btnSearchFirst tb.InlineButton
+	btnSearchPrev  tb.InlineButton
+	btnSearchNext  tb.InlineButton
+	btnSearchLast  tb.InlineButton
+
+	// меню /autopost: Data — час, набор дней (storage.Weekdays)
+	btnAutopostHour   tb.InlineButton
+	btnAutopostDays   tb.InlineButton
+	btnAutopostToggle tb.InlineButton
+	// дни, выбранные в меню /autopost до появления расписания
+	autopostDays map[int64]storage.Weekdays
+
+	// редактор настроек: Data — ключ или ключ=значение
+	btnSettingEdit  tb.InlineButton
+	btnSettingValue tb.InlineButton
+	btnSettingList  tb.InlineButton
+
+	// фильтр и страница /audit
+	auditFilter map[int64]storage.AuditFilter
+	auditPage   map[int64]int
+	// кнопки навигации и выгрузки /audit
+	btnAuditFirst tb.InlineButton
+	btnAuditPrev  tb.InlineButton
+	btnAuditNext  tb.InlineButton
+	btnAuditLast  tb.InlineButton
+	btnAuditCSV   tb.InlineButton
+
+	// /unread: следующая порция и «прочитать всё»
+	btnUnreadMore  tb.InlineButton
+	btnMarkAllRead tb.InlineButton
+
+	// переключение графика /stats, Data — график|дней
+	btnStats tb.InlineButton
+
+	// подтверждение и отмена /deleteme; текст зависит от языка пользователя
+	btnDeleteMe     tb.InlineButton
+	btnDeleteCancel tb.InlineButton
+
+	// удаление ценового алерта, Data — ID алерта
+	btnAlertDel tb.InlineButton
+
+	// подписка на источник и отписка в /mysources, Data — sourceKey
+	btnSourceToggle tb.InlineButton
 }
 
-func New(token string, db *sql.DB) *Bot {
+func New(token string, store *storage.Store) *Bot {
 	pref := tb.Settings{
 		Token:  token,
 		Poller: &tb.LongPoller{Timeout: 10 * time.Second},
@@ -33,52 +90,186 @@ func New(token string, db *sql.DB) *Bot {
 	if err != nil {
 		log.Fatalf("Ошибка создания бота: %v", err)
 	}
+	return NewWithBot(b, store)
+}
 
+// NewWithBot собирает бота поверх готового telebot: в тестах — без сети,
+// с Offline и адресом API на тестовом сервере
+func NewWithBot(b *tb.Bot, store *storage.Store) *Bot {
 	botInstance := &Bot{
-		bot:        b,
-		db:         db,
-		pending:    make(map[int64]string),
-		latestPage: make(map[int64]int),
+		bot:            b,
+		store:          store,
+		pending:        make(map[int64]string),
+		latestTotals:   make(map[int64]latestTotal),
+		latestExpanded: make(map[int64]bool),
+		searchQuery:    make(map[int64]storage.SearchQuery),
+		searchPage:     make(map[int64]int),
+		autopostDays:   make(map[int64]storage.Weekdays),
+		auditFilter:    make(map[int64]storage.AuditFilter),
+		auditPage:      make(map[int64]int),
 
 		btnFirst: tb.InlineButton{Unique: "latest_first", Text: "⏮"},
 		btnPrev:  tb.InlineButton{Unique: "latest_prev", Text: "⬅️"},
 		btnNext:  tb.InlineButton{Unique: "latest_next", Text: "➡️"},
 		btnLast:  tb.InlineButton{Unique: "latest_last", Text: "⏭"},
+		btnMore:  tb.InlineButton{Unique: "latest_more", Text: "Подробнее"},
+		btnLess:  tb.InlineButton{Unique: "latest_less", Text: "Свернуть"},
+
+		btnSearchFirst: tb.InlineButton{Unique: "search_first", Text: "⏮"},
+		btnSearchPrev:  tb.InlineButton{Unique: "search_prev", Text: "⬅️"},
+		btnSearchNext:  tb.InlineButton{Unique: "search_next", Text: "➡️"},
+		btnSearchLast:  tb.InlineButton{Unique: "search_last", Text: "⏭"},
+
+		btnAuditFirst: tb.InlineButton{Unique: "audit_first", Text: "⏮"},
+		btnAuditPrev:  tb.InlineButton{Unique: "audit_prev", Text: "⬅️"},
+		btnAuditNext:  tb.InlineButton{Unique: "audit_next", Text: "➡️"},
+		btnAuditLast:  tb.InlineButton{Unique: "audit_last", Text: "⏭"},
+		btnAuditCSV:   tb.InlineButton{Unique: "audit_csv", Text: "📄 CSV"},
+
+		btnAutopostHour:   tb.InlineButton{Unique: "ap_hour"},
+		btnAutopostDays:   tb.InlineButton{Unique: "ap_days"},
+		btnAutopostToggle: tb.InlineButton{Unique: "ap_toggle"},
+
+		btnSettingEdit:  tb.InlineButton{Unique: "set_edit"},
+		btnSettingValue: tb.InlineButton{Unique: "set_value"},
+		btnSettingList:  tb.InlineButton{Unique: "set_list", Text: "⬅️ Назад"},
+
+		btnUnreadMore:  tb.InlineButton{Unique: "unread_more", Text: "Ещё ➡️"},
+		btnMarkAllRead: tb.InlineButton{Unique: "mark_all_read", Text: "✅ Отметить все прочитанными"},
+
+		btnStats: tb.InlineButton{Unique: "stats"},
+
+		btnDeleteMe:     tb.InlineButton{Unique: "delete_me"},
+		btnDeleteCancel: tb.InlineButton{Unique: "delete_me_cancel"},
+
+		btnAlertDel: tb.InlineButton{Unique: "alert_del"},
+
+		btnSourceToggle: tb.InlineButton{Unique: "src_toggle"},
 	}
 
-	// Навигация /latest
+	// Профиль пользователя обновляется до любого обработчика
+	botInstance.bot.Use(botInstance.trackUser)
+
+	// Навигация /latest: позиция приходит в callback data
+	showLatest := func(c tb.Context, dir storage.PageDirection) error {
+		pos, _ := parseLatestPos(c.Data())
+		botInstance.ShowLatestNews(c.Sender().ID, c, dir, pos)
+		return nil
+	}
 	botInstance.bot.Handle(&botInstance.btnFirst, func(c tb.Context) error {
+		return showLatest(c, storage.PageFirst)
+	})
+	botInstance.bot.Handle(&botInstance.btnPrev, func(c tb.Context) error {
+		return showLatest(c, storage.PageBefore)
+	})
+	botInstance.bot.Handle(&botInstance.btnNext, func(c tb.Context) error {
+		return showLatest(c, storage.PageAfter)
+	})
+	botInstance.bot.Handle(&botInstance.btnLast, func(c tb.Context) error {
+		return showLatest(c, storage.PageLast)
+	})
+
+	botInstance.bot.Handle(&botInstance.btnMore, func(c tb.Context) error {
+		botInstance.latestExpanded[c.Sender().ID] = true
+		return showLatest(c, storage.PageAt)
+	})
+	botInstance.bot.Handle(&botInstance.btnLess, func(c tb.Context) error {
+		botInstance.latestExpanded[c.Sender().ID] = false
+		return showLatest(c, storage.PageAt)
+	})
+
+	// Навигация /search; за пределы ShowSearchResults страницу не пустит
+	botInstance.bot.Handle(&botInstance.btnSearchFirst, func(c tb.Context) error {
 		chatID := c.Sender().ID
-		botInstance.latestPage[chatID] = 1
-		botInstance.ShowLatestNews(chatID, c)
+		botInstance.searchPage[chatID] = 1
+		botInstance.ShowSearchResults(chatID, c)
 		return nil
 	})
-	botInstance.bot.Handle(&botInstance.btnPrev, func(c tb.Context) error {
+	botInstance.bot.Handle(&botInstance.btnSearchPrev, func(c tb.Context) error {
 		chatID := c.Sender().ID
-		if botInstance.latestPage[chatID] > 1 {
-			botInstance.latestPage[chatID]--
-		}
-		botInstance.ShowLatestNews(chatID, c)
+		botInstance.searchPage[chatID]--
+		botInstance.ShowSearchResults(chatID, c)
 		return nil
 	})
-	botInstance.bot.Handle(&botInstance.btnNext, func(c tb.Context) error {
+	botInstance.bot.Handle(&botInstance.btnSearchNext, func(c tb.Context) error {
 		chatID := c.Sender().ID
-		botInstance.latestPage[chatID]++
-		botInstance.ShowLatestNews(chatID, c)
+		botInstance.searchPage[chatID]++
+		botInstance.ShowSearchResults(chatID, c)
 		return nil
 	})
-	botInstance.bot.Handle(&botInstance.btnLast, func(c tb.Context) error {
+	botInstance.bot.Handle(&botInstance.btnSearchLast, func(c tb.Context) error {
 		chatID := c.Sender().ID
-		totalCount, _ := storage.GetTodayNewsCountForUser(botInstance.db, chatID)
-		pageSize := 4
-		totalPages := (totalCount + pageSize - 1) / pageSize
-		if totalPages < 1 {
-			totalPages = 1
+		botInstance.searchPage[chatID] = math.MaxInt32
+		botInstance.ShowSearchResults(chatID, c)
+		return nil
+	})
+
+	// Меню /autopost
+	botInstance.bot.Handle(&botInstance.btnAutopostHour, botInstance.toggleAutopostHour)
+	botInstance.bot.Handle(&botInstance.btnAutopostDays, botInstance.setAutopostDays)
+	botInstance.bot.Handle(&botInstance.btnAutopostToggle, botInstance.toggleAutopost)
+
+	// Редактор настроек
+	botInstance.bot.Handle(&botInstance.btnSettingEdit, botInstance.editSetting)
+	botInstance.bot.Handle(&botInstance.btnSettingValue, botInstance.setSettingValue)
+	botInstance.bot.Handle(&botInstance.btnSettingList, func(c tb.Context) error {
+		if botInstance.IsAdmin(c.Sender().ID) {
+			botInstance.ShowSettings(c.Sender().ID, c)
 		}
-		botInstance.latestPage[chatID] = totalPages
-		botInstance.ShowLatestNews(chatID, c)
+		return c.Respond()
+	})
+
+	// Навигация и выгрузка /audit
+	auditNav := func(move func(chatID int64)) func(c tb.Context) error {
+		return func(c tb.Context) error {
+			chatID := c.Sender().ID
+			if botInstance.IsAdmin(chatID) {
+				move(chatID)
+				botInstance.ShowAuditLog(chatID, c)
+			}
+			return c.Respond()
+		}
+	}
+	botInstance.bot.Handle(&botInstance.btnAuditFirst, auditNav(func(chatID int64) { botInstance.auditPage[chatID] = 1 }))
+	botInstance.bot.Handle(&botInstance.btnAuditPrev, auditNav(func(chatID int64) { botInstance.auditPage[chatID]-- }))
+	botInstance.bot.Handle(&botInstance.btnAuditNext, auditNav(func(chatID int64) { botInstance.auditPage[chatID]++ }))
+	botInstance.bot.Handle(&botInstance.btnAuditLast, auditNav(func(chatID int64) { botInstance.auditPage[chatID] = math.MaxInt32 }))
+	botInstance.bot.Handle(&botInstance.btnAuditCSV, func(c tb.Context) error {
+		if botInstance.IsAdmin(c.Sender().ID) {
+			botInstance.SendAuditCSV(c.Sender().ID)
+		}
+		return c.Respond()
+	})
+
+	// Непрочитанное
+	botInstance.bot.Handle(&botInstance.btnUnreadMore, func(c tb.Context) error {
+		botInstance.ShowUnread(c.Sender().ID, c)
 		return nil
 	})
+	botInstance.bot.Handle(&botInstance.btnMarkAllRead, botInstance.MarkAllRead)
+
+	// Графики /stats
+	botInstance.bot.Handle(&botInstance.btnStats, botInstance.statsButton)
+
+	// Подтверждение /deleteme
+	botInstance.bot.Handle(&botInstance.btnDeleteMe, botInstance.deleteMe)
+	botInstance.bot.Handle(&botInstance.btnDeleteCancel, botInstance.cancelDeleteMe)
+
+	// Удаление алерта из /alerts
+	botInstance.bot.Handle(&botInstance.btnAlertDel, func(c tb.Context) error {
+		chatID := c.Sender().ID
+		if id, err := strconv.ParseInt(c.Data(), 10, 64); err == nil {
+			_ = botInstance.store.Alerts.DeletePriceAlert(chatID, id)
+		}
+		botInstance.ShowAlerts(chatID, c)
+		return c.Respond(&tb.CallbackResponse{Text: "Алерт удалён"})
+	})
+
+	// Подписки /mysources
+	botInstance.bot.Handle(&botInstance.btnSourceToggle, botInstance.toggleSource)
+
+	// Загрузка .ics админом
+	botInstance.bot.Handle(tb.OnDocument, botInstance.HandleCalendarUpload)
 
 	// Текстовые сообщения
 	botInstance.bot.Handle(tb.OnText, func(c tb.Context) error {
@@ -94,7 +285,7 @@ func (b *Bot) Start() {
 }
 
 func (b *Bot) SendMessage(chatID int64, text string) {
-	_, _ = b.bot.Send(tb.ChatID(chatID), text)
+	_, _ = b.send(chatID, text)
 }
 
 // Проверка на админа
@@ -106,19 +297,15 @@ func (b *Bot) IsAdmin(userID int64) bool {
 	return AdminIDs[userID]
 }
 
+// Московское время для расписаний и отображения дат
+var moscow = time.FixedZone("MSK", 3*60*60)
+
 func (b *Bot) StartNewsUpdater() {
-    ticker := time.NewTicker(10 * time.Minute) // интервал обновления
-    for range ticker.C {
-        newsMap, err := storage.FetchAndStoreNews(b.db)
-        if err != nil {
-            log.Printf("Ошибка обновления новостей: %v", err)
-            continue
-        }
-
-        for userID, newsItems := range newsMap {
-            for _, n := range newsItems {
-                b.SendMessage(userID, fmt.Sprintf("📰 %s\n%s", n.Title, n.Link))
-            }
-        }
-    }
+	ticker := time.NewTicker(10 * time.Minute) // интервал обновления
+	for range ticker.C {
+		// рассылку новых новостей ведёт StartOutboxSender
+		if _, err := ingest.FetchAndStore(b.store); err != nil {
+			log.Printf("Ошибка обновления новостей: %v", err)
+		}
+	}
 }
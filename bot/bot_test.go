@@ -0,0 +1,268 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage/memory"
+	tb "gopkg.in/telebot.v3"
+)
+
+const testUserID = 1001
+
+// apiCall — запрос бота к Telegram API
+type apiCall struct {
+	method  string
+	payload map[string]interface{}
+}
+
+// text — текст сообщения или ответа на нажатие кнопки
+func (c apiCall) text() string {
+	s, _ := c.payload["text"].(string)
+	return s
+}
+
+// buttons — подписи inline-кнопок сообщения
+func (c apiCall) buttons() []string {
+	raw, _ := c.payload["reply_markup"].(string)
+	var markup struct {
+		InlineKeyboard [][]struct {
+			Text string `json:"text"`
+		} `json:"inline_keyboard"`
+	}
+	_ = json.Unmarshal([]byte(raw), &markup)
+	var out []string
+	for _, row := range markup.InlineKeyboard {
+		for _, btn := range row {
+			out = append(out, btn.Text)
+		}
+	}
+	return out
+}
+
+// testEnv — бот на хранилище в памяти и тестовый сервер вместо Telegram API.
+// Сервер же отдаёт RSS-фид по /feed.xml
+type testEnv struct {
+	t     *testing.T
+	bot   *Bot
+	store *storage.Store
+	srv   *httptest.Server
+
+	mu    sync.Mutex
+	calls []apiCall
+	feed  string
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	env := &testEnv{t: t, store: memory.New()}
+	env.srv = httptest.NewServer(http.HandlerFunc(env.serve))
+	t.Cleanup(env.srv.Close)
+
+	tbot, err := tb.NewBot(tb.Settings{
+		URL:         env.srv.URL,
+		Token:       "test",
+		Offline:     true,
+		Synchronous: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.bot = NewWithBot(tbot, env.store)
+	return env
+}
+
+func (env *testEnv) serve(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/feed.xml" {
+		env.mu.Lock()
+		feed := env.feed
+		env.mu.Unlock()
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, feed)
+		return
+	}
+
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	payload := make(map[string]interface{})
+	_ = json.NewDecoder(r.Body).Decode(&payload)
+	env.mu.Lock()
+	env.calls = append(env.calls, apiCall{method: method, payload: payload})
+	env.mu.Unlock()
+
+	if method == "answerCallbackQuery" {
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+		return
+	}
+	chatID, _ := strconv.ParseInt(fmt.Sprint(payload["chat_id"]), 10, 64)
+	fmt.Fprintf(w, `{"ok":true,"result":{"message_id":1,"date":%d,"chat":{"id":%d,"type":"private"}}}`,
+		time.Now().Unix(), chatID)
+}
+
+// send отправляет боту текстовое сообщение от тестового пользователя
+func (env *testEnv) send(text string) {
+	user := &tb.User{ID: testUserID, FirstName: "Test", LanguageCode: "ru"}
+	env.bot.bot.ProcessUpdate(tb.Update{Message: &tb.Message{
+		Text:   text,
+		Sender: user,
+		Chat:   &tb.Chat{ID: testUserID, Type: tb.ChatPrivate},
+	}})
+}
+
+// press нажимает inline-кнопку под сообщением бота
+func (env *testEnv) press(btn tb.InlineButton, data string) {
+	user := &tb.User{ID: testUserID, FirstName: "Test", LanguageCode: "ru"}
+	env.bot.bot.ProcessUpdate(tb.Update{Callback: &tb.Callback{
+		ID:      "1",
+		Sender:  user,
+		Message: &tb.Message{ID: 1, Chat: &tb.Chat{ID: testUserID, Type: tb.ChatPrivate}},
+		Data:    "\f" + btn.Unique + "|" + data,
+	}})
+}
+
+// last возвращает последний вызов method и очищает журнал
+func (env *testEnv) last(method string) apiCall {
+	env.t.Helper()
+	env.mu.Lock()
+	defer env.mu.Unlock()
+	calls := env.calls
+	env.calls = nil
+	for i := len(calls) - 1; i >= 0; i-- {
+		if calls[i].method == method {
+			return calls[i]
+		}
+	}
+	env.t.Fatalf("бот не вызывал %s, вызовы: %v", method, calls)
+	return apiCall{}
+}
+
+// flush очищает журнал и возвращает число вызовов в нём
+func (env *testEnv) flush() int {
+	env.mu.Lock()
+	defer env.mu.Unlock()
+	n := len(env.calls)
+	env.calls = nil
+	return n
+}
+
+func (env *testEnv) addSource(url, title string) {
+	env.t.Helper()
+	if err := env.store.Sources.AddSource(url, 0); err != nil {
+		env.t.Fatal(err)
+	}
+	if title != "" {
+		if _, err := env.store.Sources.UpdateSource(storage.Source{URL: url, Title: title}); err != nil {
+			env.t.Fatal(err)
+		}
+	}
+}
+
+func TestSubscribeToggle(t *testing.T) {
+	env := newTestEnv(t)
+	alpha := "https://alpha.example/rss"
+	env.addSource(alpha, "Alpha")
+	env.addSource("https://beta.example/rss", "Beta")
+
+	env.send("/mysources")
+	menu := env.last("sendMessage")
+	if !strings.Contains(menu.text(), "Alpha") || !strings.Contains(menu.text(), "Beta") {
+		t.Fatalf("в меню нет источников: %q", menu.text())
+	}
+	if got := menu.buttons(); len(got) != 2 || got[0] != "▫️ Alpha" {
+		t.Fatalf("кнопки меню: %q", got)
+	}
+
+	env.press(env.bot.btnSourceToggle, sourceKey(alpha))
+	if got := env.last("answerCallbackQuery").text(); got != "✅ Подписка оформлена" {
+		t.Errorf("ответ на подписку: %q", got)
+	}
+	subs, _ := env.store.Subscriptions.GetUserSubscriptions(testUserID)
+	if len(subs) != 1 || subs[0] != alpha {
+		t.Fatalf("подписки после подписки: %v", subs)
+	}
+
+	env.press(env.bot.btnSourceToggle, sourceKey(alpha))
+	edit := env.last("editMessageText")
+	if got := edit.buttons(); len(got) != 2 || got[0] != "▫️ Alpha" {
+		t.Errorf("кнопки после отписки: %q", got)
+	}
+	if n, _ := env.store.Subscriptions.GetUserSubscriptionCount(testUserID); n != 0 {
+		t.Fatalf("подписок после отписки: %d", n)
+	}
+
+	env.press(env.bot.btnSourceToggle, sourceKey(alpha))
+	if got := env.last("editMessageText").buttons(); len(got) != 2 || got[0] != "✅ Alpha" {
+		t.Errorf("кнопки после повторной подписки: %q", got)
+	}
+}
+
+func TestLatest(t *testing.T) {
+	env := newTestEnv(t)
+	env.send("/latest")
+	if got := env.last("sendMessage").text(); !strings.Contains(got, "Новостей нет") {
+		t.Errorf("/latest без подписок: %q", got)
+	}
+
+	pub := time.Now().UTC().Add(-time.Hour).Format(time.RFC1123Z)
+	env.feed = `<?xml version="1.0"?><rss version="2.0"><channel>
+		<title>Test Feed</title><description>Лента для теста</description><language>ru-RU</language>
+		<item><title>Первая новость</title><link>https://news.example/1</link><pubDate>` + pub + `</pubDate></item>
+		<item><title>Вторая новость</title><link>https://news.example/2</link><pubDate>` + pub + `</pubDate></item>
+	</channel></rss>`
+	feedURL := env.srv.URL + "/feed.xml"
+	env.addSource(feedURL, "")
+	if _, err := env.store.Subscriptions.Subscribe(testUserID, feedURL); err != nil {
+		t.Fatal(err)
+	}
+
+	env.send("/latest")
+	got := env.last("sendMessage").text()
+	for _, want := range []string{"Первая новость", "Вторая новость", "https://news.example/1", "🗞 Test Feed"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("в /latest нет %q: %q", want, got)
+		}
+	}
+	// название источника подставилось из фида
+	if src, _, _ := env.store.Sources.GetSource(feedURL); src.Title != "Test Feed" || src.Language != "ru" {
+		t.Errorf("метаданные источника из фида: %+v", src)
+	}
+	if unread, _ := env.store.Reads.CountUnreadNewsForUser(testUserID); unread != 0 {
+		t.Errorf("показанные новости не отмечены прочитанными: %d", unread)
+	}
+}
+
+func TestWatchlist(t *testing.T) {
+	env := newTestEnv(t)
+
+	env.send("/watch sber")
+	if got := env.last("sendMessage").text(); !strings.Contains(got, "SBER добавлен") {
+		t.Errorf("ответ /watch: %q", got)
+	}
+	env.send("/watch GAZP")
+	env.last("sendMessage")
+
+	env.send("/watchlist")
+	got := env.last("sendMessage").text()
+	if !strings.Contains(got, "#SBER") || !strings.Contains(got, "#GAZP") {
+		t.Errorf("/watchlist: %q", got)
+	}
+
+	env.send("/unwatch SBER")
+	if got := env.last("sendMessage").text(); !strings.Contains(got, "SBER удалён") {
+		t.Errorf("ответ /unwatch: %q", got)
+	}
+	env.send("/unwatch SBER")
+	if got := env.last("sendMessage").text(); !strings.Contains(got, "нет в вашем watchlist") {
+		t.Errorf("повторный /unwatch: %q", got)
+	}
+	if tickers, _ := env.store.Watchlist.GetWatchlist(testUserID); len(tickers) != 1 || tickers[0] != "GAZP" {
+		t.Errorf("watchlist после /unwatch: %v", tickers)
+	}
+}
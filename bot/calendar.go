@@ -0,0 +1,302 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/calendar"
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+	tb "gopkg.in/telebot.v3"
+)
+
+const (
+	// как часто перечитывать ICS-календари
+	calendarRefreshInterval = time.Hour
+	// напоминание по умолчанию, минут до события
+	defaultRemindBefore = 15
+	// новости, пришедшие за eventNewsWindow до и после начала события,
+	// отправляются через eventNewsWindow после его начала
+	eventNewsWindow = 30 * time.Minute
+	eventNewsLimit  = 5
+)
+
+var weekdays = [...]string{"Вс", "Пн", "Вт", "Ср", "Чт", "Пт", "Сб"}
+
+// userLocation возвращает часовой пояс пользователя
+func (b *Bot) userLocation(userID int64) *time.Location {
+	tz, err := b.store.Prefs.GetTimezone(userID)
+	if err != nil {
+		return moscow
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return moscow
+	}
+	return loc
+}
+
+// StartCalendarWorker обновляет календари и рассылает напоминания о событиях
+func (b *Bot) StartCalendarWorker() {
+	var lastRefresh time.Time
+	ticker := time.NewTicker(time.Minute)
+	for range ticker.C {
+		if time.Since(lastRefresh) >= calendarRefreshInterval {
+			b.refreshCalendars()
+			lastRefresh = time.Now()
+		}
+		b.sendEventReminders()
+		b.sendEventNews()
+	}
+}
+
+func (b *Bot) refreshCalendars() {
+	urls, err := b.store.Calendar.GetCalendarSources()
+	if err != nil {
+		log.Printf("Ошибка выборки календарей: %v", err)
+		return
+	}
+	for _, u := range urls {
+		events, err := calendar.Fetch(u)
+		if err != nil {
+			log.Printf("Ошибка загрузки календаря %s: %v", u, err)
+			continue
+		}
+		if err := b.store.Calendar.SaveEvents(u, events); err != nil {
+			log.Printf("Ошибка сохранения событий %s: %v", u, err)
+		}
+	}
+}
+
+func (b *Bot) sendEventReminders() {
+	due, err := b.store.Calendar.GetDueEventReminders()
+	if err != nil {
+		log.Printf("Ошибка выборки напоминаний: %v", err)
+		return
+	}
+	for _, n := range due {
+		if ok, err := b.store.Calendar.MarkEventNotified(n.UserID, n.Event.UID, storage.EventNotifyReminder); err != nil || !ok {
+			continue
+		}
+		loc := b.userLocation(n.UserID)
+		minutes := int(time.Until(n.Event.Start).Minutes() + 0.5)
+		msg := fmt.Sprintf("⏰ Через %d мин: %s\n%s", minutes, n.Event.Title, formatEventTime(n.Event, loc))
+		if n.Event.Description != "" {
+			msg += "\n\n" + n.Event.Description
+		}
+		b.SendMessage(n.UserID, msg)
+	}
+}
+
+// sendEventNews отправляет новости, вышедшие вокруг начала события
+func (b *Bot) sendEventNews() {
+	due, err := b.store.Calendar.GetDueEventFollowups(eventNewsWindow)
+	if err != nil {
+		log.Printf("Ошибка выборки событий: %v", err)
+		return
+	}
+	for _, n := range due {
+		if ok, err := b.store.Calendar.MarkEventNotified(n.UserID, n.Event.UID, storage.EventNotifyNews); err != nil || !ok {
+			continue
+		}
+		from := n.Event.Start.Add(-eventNewsWindow)
+		to := n.Event.Start.Add(eventNewsWindow)
+		news, _ := b.store.News.GetNewsBetweenForUser(n.UserID, from, to, eventNewsLimit)
+		if len(news) == 0 {
+			continue
+		}
+		msg := "🗞 Новости вокруг события «" + n.Event.Title + "»:"
+		for _, item := range news {
+			msg += fmt.Sprintf("\n\n• %s\n%s", item.Title, item.Link)
+		}
+		b.SendMessage(n.UserID, msg)
+	}
+}
+
+func formatEventTime(e calendar.Event, loc *time.Location) string {
+	if e.AllDay {
+		return e.Start.Format("02.01") + " весь день"
+	}
+	t := e.Start.In(loc)
+	return fmt.Sprintf("%s %s", weekdays[t.Weekday()], t.Format("02.01 15:04"))
+}
+
+// ShowCalendar показывает события на сегодня и на неделю в часовом поясе пользователя
+func (b *Bot) ShowCalendar(userID int64) {
+	loc := b.userLocation(userID)
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	tomorrow := today.AddDate(0, 0, 1)
+	weekEnd := today.AddDate(0, 0, 7)
+
+	// события на весь день хранятся датой (полночь UTC): выборка шире на сутки
+	// с обеих сторон, а по дням события раскладываются по началу в поясе пользователя
+	events, _ := b.store.Calendar.GetEventsBetween(today.AddDate(0, 0, -1), weekEnd.AddDate(0, 0, 1))
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].StartIn(loc).Before(events[j].StartIn(loc))
+	})
+	var todayEvents, weekEvents []calendar.Event
+	for _, e := range events {
+		start := e.StartIn(loc)
+		switch {
+		case start.Before(today) || !start.Before(weekEnd):
+		case start.Before(tomorrow):
+			todayEvents = append(todayEvents, e)
+		default:
+			weekEvents = append(weekEvents, e)
+		}
+	}
+
+	out := fmt.Sprintf("🗓 Сегодня (%s):\n", loc)
+	if len(todayEvents) == 0 {
+		out += "событий нет\n"
+	}
+	for _, e := range todayEvents {
+		out += formatEventLine(e, loc, false)
+	}
+
+	out += "\n📅 Ближайшие 7 дней:\n"
+	if len(weekEvents) == 0 {
+		out += "событий нет\n"
+	}
+	for _, e := range weekEvents {
+		out += formatEventLine(e, loc, true)
+	}
+
+	subs, _ := b.store.Calendar.GetCalendarSubscriptions(userID)
+	if len(subs) > 0 {
+		out += "\n🔔 Напоминания:"
+		for c, m := range subs {
+			out += fmt.Sprintf(" %s (за %d мин)", c, m)
+		}
+		out += "\n"
+	}
+	cats, _ := b.store.Calendar.GetEventCategories()
+	if len(cats) > 0 {
+		out += "\nКатегории: " + strings.Join(cats, ", ")
+	}
+	out += "\n/calsub <категория|all> [минут] – напоминания\n/timezone <пояс> – часовой пояс"
+	b.SendMessage(userID, out)
+}
+
+func formatEventLine(e calendar.Event, loc *time.Location, withDate bool) string {
+	when := "весь день"
+	if !e.AllDay {
+		when = e.Start.In(loc).Format("15:04")
+	}
+	if withDate {
+		when = formatEventTime(e, loc)
+	}
+	return fmt.Sprintf("%s [%s] %s\n", when, e.Category, e.Title)
+}
+
+func (b *Bot) HandleCalendarSubscribe(userID int64, arg string) {
+	parts := strings.Fields(arg)
+	if len(parts) == 0 || len(parts) > 2 {
+		b.SendMessage(userID, "⚠️ Формат: /calsub cpi 30")
+		return
+	}
+	category := calendar.NormalizeCategory(parts[0])
+	minutes := defaultRemindBefore
+	if len(parts) == 2 {
+		m, err := strconv.Atoi(parts[1])
+		if err != nil || m < 0 || m > 24*60 {
+			b.SendMessage(userID, "⚠️ Минуты: число от 0 до 1440")
+			return
+		}
+		minutes = m
+	}
+	if err := b.store.Calendar.SubscribeCalendar(userID, category, minutes); err != nil {
+		b.SendMessage(userID, "❌ Ошибка подписки")
+		return
+	}
+	b.SendMessage(userID, fmt.Sprintf("✅ Напоминания о событиях «%s» за %d мин", category, minutes))
+}
+
+func (b *Bot) HandleCalendarUnsubscribe(userID int64, arg string) {
+	category := calendar.NormalizeCategory(arg)
+	removed, err := b.store.Calendar.UnsubscribeCalendar(userID, category)
+	switch {
+	case err != nil:
+		b.SendMessage(userID, "❌ Ошибка отписки")
+	case !removed:
+		b.SendMessage(userID, "⚠️ Нет подписки на «"+category+"»")
+	default:
+		b.SendMessage(userID, "✅ Напоминания о «"+category+"» отключены")
+	}
+}
+
+func (b *Bot) HandleTimezone(userID int64, arg string) {
+	if arg == "" {
+		b.SendMessage(userID, "Ваш часовой пояс: "+b.userLocation(userID).String()+"\nИзменить: /timezone Europe/Moscow")
+		return
+	}
+	if _, err := time.LoadLocation(arg); err != nil {
+		b.SendMessage(userID, "⚠️ Неизвестный часовой пояс. Пример: Europe/Moscow, Asia/Yekaterinburg")
+		return
+	}
+	if err := b.store.Prefs.SetTimezone(userID, arg); err != nil {
+		b.SendMessage(userID, "❌ Ошибка сохранения часового пояса")
+		return
+	}
+	// время дайджеста остаётся тем же по местным часам
+	if schedules, _ := b.store.Autopost.GetUserAutopost(userID); len(schedules) > 0 {
+		for i := range schedules {
+			schedules[i].Timezone = arg
+		}
+		_ = b.store.Autopost.SetUserAutopost(userID, schedules)
+	}
+	b.SendMessage(userID, "✅ Часовой пояс: "+arg)
+}
+
+func (b *Bot) HandleAddCalendar(userID int64, url string) {
+	var err error
+	defer func() { b.audit(userID, "addcalendar", url, resultOf(err)) }()
+	events, err := calendar.Fetch(url)
+	if err != nil {
+		b.SendMessage(userID, "❌ Не удалось загрузить календарь: "+err.Error())
+		return
+	}
+	if err = b.store.Calendar.AddCalendarSource(url); err != nil {
+		b.SendMessage(userID, "❌ Ошибка добавления календаря")
+		return
+	}
+	if err = b.store.Calendar.SaveEvents(url, events); err != nil {
+		b.SendMessage(userID, "❌ Ошибка сохранения событий")
+		return
+	}
+	b.SendMessage(userID, fmt.Sprintf("✅ Календарь добавлен, событий: %d", len(events)))
+}
+
+// HandleCalendarUpload принимает .ics-файл от админа
+func (b *Bot) HandleCalendarUpload(c tb.Context) error {
+	userID := c.Sender().ID
+	doc := c.Message().Document
+	if !b.IsAdmin(userID) || doc == nil || !strings.HasSuffix(strings.ToLower(doc.FileName), ".ics") {
+		return nil
+	}
+
+	var err error
+	defer func() { b.audit(userID, "uploadcalendar", doc.FileName, resultOf(err)) }()
+	rc, err := b.bot.File(&doc.File)
+	if err != nil {
+		b.SendMessage(userID, "❌ Не удалось скачать файл")
+		return nil
+	}
+	defer rc.Close()
+
+	events, err := calendar.Parse(rc)
+	if err != nil {
+		b.SendMessage(userID, "❌ Ошибка разбора ICS: "+err.Error())
+		return nil
+	}
+	if err = b.store.Calendar.SaveEvents("upload:"+doc.FileName, events); err != nil {
+		b.SendMessage(userID, "❌ Ошибка сохранения событий")
+		return nil
+	}
+	b.SendMessage(userID, fmt.Sprintf("✅ Загружено событий: %d", len(events)))
+	return nil
+}
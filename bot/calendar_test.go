@@ -0,0 +1,40 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/calendar"
+)
+
+// Событие на весь день попадает в «сегодня» по дате, а не по полуночи UTC:
+// в поясе с отрицательным смещением полночь UTC — ещё вчера
+func TestCalendarAllDayInUserZone(t *testing.T) {
+	env := newTestEnv(t)
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.store.Prefs.SetTimezone(testUserID, loc.String()); err != nil {
+		t.Fatal(err)
+	}
+	y, m, d := time.Now().In(loc).Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	if err := env.store.Calendar.SaveEvents("https://calendar.example/ics", []calendar.Event{
+		{UID: "today", Title: "Сегодняшнее событие", Category: "other", Start: today, AllDay: true},
+		{UID: "tomorrow", Title: "Завтрашнее событие", Category: "other", Start: today.AddDate(0, 0, 1), AllDay: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	env.send("/calendar")
+	got := env.last("sendMessage").text()
+	todayPart, weekPart, _ := strings.Cut(got, "Ближайшие 7 дней")
+	if !strings.Contains(todayPart, "Сегодняшнее событие") {
+		t.Errorf("событие на весь день не попало в «сегодня»: %q", got)
+	}
+	if !strings.Contains(weekPart, "Завтрашнее событие") || strings.Contains(weekPart, "Сегодняшнее событие") {
+		t.Errorf("события недели: %q", got)
+	}
+}
@@ -0,0 +1,61 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/tagger"
+)
+
+// Лимит длины сообщения Telegram с запасом
+const maxMessageLen = 4000
+
+// StartDigestSender раз в минуту отправляет отложенные новости
+// пользователям, у которых наступило время авторассылки
+func (b *Bot) StartDigestSender() {
+	ticker := time.NewTicker(time.Minute)
+	for now := range ticker.C {
+		schedules, err := b.store.Autopost.GetEnabledAutoposts()
+		if err != nil {
+			log.Printf("Ошибка выборки авторассылки: %v", err)
+			continue
+		}
+		sent := make(map[int64]bool)
+		for _, s := range schedules {
+			if !sent[s.UserID] && s.Due(now) {
+				sent[s.UserID] = true
+				b.SendDigest(s.UserID)
+			}
+		}
+	}
+}
+
+// SendDigest отправляет пользователю накопленные новости одним или несколькими сообщениями
+func (b *Bot) SendDigest(userID int64) {
+	news, err := b.store.News.PopDeferredNews(userID)
+	if err != nil {
+		log.Printf("Ошибка выборки отложенных новостей: %v", err)
+		return
+	}
+	if len(news) == 0 {
+		return
+	}
+
+	msg := fmt.Sprintf("🗞 Дайджест: %d новостей\n", len(news))
+	names := b.sourceNames()
+	for _, n := range news {
+		entry := fmt.Sprintf("\n• %s\n🗞 %s\n", n.Title, sourceLabel(names, n.Source))
+		if len(n.Tags) > 0 {
+			entry += tagger.Hashtags(n.Tags) + "\n"
+		}
+		entry += n.Link + "\n"
+		if len(msg)+len(entry) > maxMessageLen {
+			b.SendMessage(userID, msg)
+			msg = ""
+		}
+		msg += entry
+	}
+	b.SendMessage(userID, msg)
+	b.markRead(userID, news)
+}
@@ -3,26 +3,41 @@ package bot
 import (
 	"fmt"
 	"log"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/FFFFFFFFFFj/trade-news-bot/ingest"
+	"github.com/FFFFFFFFFFj/trade-news-bot/sentiment"
+	"github.com/FFFFFFFFFFj/trade-news-bot/settings"
 	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+	"github.com/FFFFFFFFFFj/trade-news-bot/tagger"
 	tb "gopkg.in/telebot.v3"
 )
 
 func (b *Bot) HandleMessage(m *tb.Message) {
-	_, _ = b.db.Exec(`INSERT INTO users (id) VALUES ($1) ON CONFLICT DO NOTHING`, m.Chat.ID)
 	txt := strings.TrimSpace(m.Text)
 	userID := m.Chat.ID
 
 	// Проверка режима ввода админских команд
 	if mode, ok := b.pending[userID]; ok && b.IsAdmin(userID) {
+		// ввод значения настройки; команда вместо значения отменяет ввод
+		if key, ok := strings.CutPrefix(mode, pendingSetting); ok {
+			b.pending[userID] = ""
+			if !strings.HasPrefix(txt, "/") {
+				b.HandlePendingSetting(userID, key, txt)
+				return
+			}
+		}
 		switch mode {
 		case "addsource":
 			if txt == "" {
 				b.SendMessage(userID, "⚠️ URL пустой")
-			} else if err := storage.AddSource(b.db, txt); err != nil {
+			} else if err := b.store.Sources.AddSource(txt, userID); err != nil {
+				b.audit(userID, "addsource", txt, resultOf(err))
 				b.SendMessage(userID, "❌ Ошибка добавления источника")
 			} else {
+				b.audit(userID, "addsource", txt, resultOf(nil))
 				b.SendMessage(userID, "✅ Источник добавлен: "+txt)
 			}
 			b.pending[userID] = ""
@@ -31,9 +46,11 @@ func (b *Bot) HandleMessage(m *tb.Message) {
 		case "removesource":
 			if txt == "" {
 				b.SendMessage(userID, "⚠️ URL пустой")
-			} else if err := storage.RemoveSource(b.db, txt); err != nil {
+			} else if err := b.store.Sources.RemoveSource(txt); err != nil {
+				b.audit(userID, "removesource", txt, resultOf(err))
 				b.SendMessage(userID, "❌ Ошибка удаления источника")
 			} else {
+				b.audit(userID, "removesource", txt, resultOf(nil))
 				b.SendMessage(userID, "✅ Источник удалён: "+txt)
 			}
 			b.pending[userID] = ""
@@ -43,26 +60,29 @@ func (b *Bot) HandleMessage(m *tb.Message) {
 			if txt == "" {
 				b.SendMessage(userID, "⚠️ Сообщение пустое")
 			} else {
-				b.AdminBroadcast(txt)
+				sent, total, err := b.AdminBroadcast(txt)
+				result := resultOf(err)
+				if err == nil {
+					result = fmt.Sprintf("ok, отправлено %d из %d", sent, total)
+				}
+				b.audit(userID, "broadcast", txt, result)
 			}
 			b.pending[userID] = ""
 			return
 
 		case "setchannel":
-			if txt == "" {
-				b.SendMessage(userID, "⚠️ Ссылка пустая")
+			if _, err := b.setSetting(userID, "setchannel", settings.Channel, txt); err != nil {
+				b.SendMessage(userID, "⚠️ "+err.Error())
 			} else {
-				_ = storage.SetSetting(b.db, "channel", txt)
 				b.SendMessage(userID, "✅ Ссылка на канал обновлена")
 			}
 			b.pending[userID] = ""
 			return
 
 		case "setmanual":
-			if txt == "" {
-				b.SendMessage(userID, "⚠️ Ссылка пустая")
+			if _, err := b.setSetting(userID, "setmanual", settings.Manual, txt); err != nil {
+				b.SendMessage(userID, "⚠️ "+err.Error())
 			} else {
-				_ = storage.SetSetting(b.db, "manual", txt)
 				b.SendMessage(userID, "✅ Ссылка на инструкцию обновлена")
 			}
 			b.pending[userID] = ""
@@ -72,17 +92,23 @@ func (b *Bot) HandleMessage(m *tb.Message) {
 
 	switch {
 	case txt == "/start":
+		unread, _ := b.store.Reads.CountUnreadNewsForUser(userID)
+		var markup *tb.ReplyMarkup
+		if unread > 0 {
+			markup = &tb.ReplyMarkup{InlineKeyboard: [][]tb.InlineButton{{b.btnMarkAllRead}}}
+		}
 		if b.IsAdmin(userID) {
-			usersCount, _ := storage.GetUsersCount(b.db)
-			activeUsers, _ := storage.GetActiveUsersCount(b.db)
-			autopostUsers, _ := storage.GetAutopostUsersCount(b.db)
-			msg := fmt.Sprintf("👑 Админ\nID: %d\nВсего пользователей: %d\nПодписанных: %d\nС автопостом: %d\nВсего источников: %d",
-				userID, usersCount, activeUsers, autopostUsers, len(storage.MustGetAllSources(b.db)))
-			b.SendMessage(userID, msg)
+			usersCount, _ := b.store.Users.GetUsersCount()
+			activeUsers, _ := b.store.Users.GetActiveUsersCount()
+			autopostUsers, _ := b.store.Autopost.GetAutopostUsersCount()
+			allSources, _ := b.store.Sources.GetAllSources()
+			msg := fmt.Sprintf("👑 Админ\nID: %d\nВсего пользователей: %d\nПодписанных: %d\nС автопостом: %d\n%s\nВсего источников: %d\nНепрочитанных новостей: %d",
+				userID, usersCount, activeUsers, autopostUsers, b.userStats(), len(allSources), unread)
+			_, _ = b.bot.Send(tb.ChatID(userID), msg, markup)
 		} else {
-			subsCount, _ := storage.GetUserSubscriptionCount(b.db, userID)
-			msg := fmt.Sprintf("👤 Пользователь\nID: %d\nПодписок: %d", userID, subsCount)
-			b.SendMessage(userID, msg)
+			subsCount, _ := b.store.Subscriptions.GetUserSubscriptionCount(userID)
+			msg := b.text(userID, msgStartUser, userID, subsCount, unread)
+			_, _ = b.bot.Send(tb.ChatID(userID), msg, markup)
 		}
 
 	case txt == "/help":
@@ -91,53 +117,165 @@ func (b *Bot) HandleMessage(m *tb.Message) {
 				"/start – информация\n"+
 				"/help – список команд\n"+
 				"/latest – новости\n"+
-				"/mysources – подписки\n"+
-				"/autopost – авторассылка\n\n"+
+				"/unread – непрочитанные новости\n"+
+				"/search <запрос> – поиск по новостям\n"+
+				"/mysources – источники и подписки\n"+
+				"/autopost – авторассылка\n"+
+				"/watch <тикер> – следить за инструментом\n"+
+				"/unwatch <тикер> – перестать следить\n"+
+				"/watchlist – мой watchlist\n"+
+				"/sentiment – фильтр по тональности\n"+
+				"/quote <тикер> – котировка\n"+
+				"/showprice on|off – цена у новостей\n"+
+				"/alert <тикер> > <цена> – ценовой алерт\n"+
+				"/alerts – мои алерты\n"+
+				"/calendar – экономический календарь\n"+
+				"/calsub <категория> [мин] – напоминания о событиях\n"+
+				"/calunsub <категория> – отключить напоминания\n"+
+				"/timezone <пояс> – часовой пояс\n"+
+				"/importance <порог> – порог мгновенных уведомлений\n"+
+				"/mydata – выгрузить мои данные\n"+
+				"/deleteme – удалить мои данные\n\n"+
 				"👑 Админские:\n"+
 				"/addsource – добавить источник\n"+
 				"/removesource – удалить источник\n"+
 				"/listsources – список источников\n"+
+				"/editsource <номер|url> <поле> <значение> – название, описание, категория, язык, порядок источника\n"+
 				"/broadcast – рассылка всем\n"+
 				"/setchannel <url> – задать ссылку на канал\n"+
 				"/setmanual <url> – задать ссылку на инструкцию\n"+
-				"/getsettings – показать все настройки")
+				"/getsettings – показать все настройки\n"+
+				"/set <ключ> <значение> – изменить настройку\n"+
+				"/settinghistory <ключ> – история изменений настройки\n"+
+				"/addalias <тикер> <название> – добавить алиас инструмента\n"+
+				"/removealias <название> – удалить алиас\n"+
+				"/aliases – словарь алиасов\n"+
+				"/addcalendar <url> – добавить ICS-календарь (или пришлите .ics файлом)\n"+
+				"/removecalendar <url> – удалить календарь\n"+
+				"/calendars – список календарей\n"+
+				"/addrule <вес> <слово> – правило важности\n"+
+				"/removerule <слово> – удалить правило\n"+
+				"/rules – правила важности\n"+
+				"/setpriority <url> <число> – приоритет источника\n"+
+				"/stats – графики новостей, push-уведомлений и пользователей\n"+
+				"/user <id|@username|имя> – найти пользователя\n"+
+				"/audit [admin=<id>] [action=<действие>] [csv] – журнал действий админов\n"+
+				"/retention – срок хранения новостей")
 		} else {
-			b.SendMessage(userID, "Доступные команды:\n"+
-				"/start – информация\n"+
-				"/help – список команд\n"+
-				"/latest – новости\n"+
-				"/mysources – подписки\n"+
-				"/autopost – авторассылка")
+			b.SendMessage(userID, b.text(userID, msgHelpUser))
 		}
 
 	case strings.HasPrefix(txt, "/autopost "):
-		parts := strings.Fields(txt)[1:]
-		var validTimes []string
-		for _, p := range parts {
-			if len(p) == 5 && p[2] == ':' {
-				validTimes = append(validTimes, p)
-			}
-		}
-		if len(validTimes) > 6 {
-			b.SendMessage(userID, "⚠️ Максимум 6 времён")
-		} else if len(validTimes) == 0 {
-			b.SendMessage(userID, "⚠️ Неверный формат времени")
-		} else {
-			_ = storage.SetUserAutopost(b.db, userID, validTimes)
-			b.SendMessage(userID, "✅ Время авторассылки обновлено: "+strings.Join(validTimes, ", "))
-		}
+		b.HandleAutopost(userID, strings.TrimSpace(strings.TrimPrefix(txt, "/autopost ")))
 
 	case txt == "/autopost":
-		b.ShowAutopostMenu(userID)
+		b.ShowAutopostMenu(userID, nil)
 
 	case txt == "/latest":
 		// подгружаем новые новости только по подпискам юзера
-		_ = storage.FetchAndStoreNewsForUser(b.db, userID)
-		b.latestPage[userID] = 1
-		b.ShowLatestNews(userID, nil)
+		_ = ingest.FetchAndStoreForUser(b.store, userID)
+		b.latestExpanded[userID] = false
+		b.ShowLatestNews(userID, nil, storage.PageFirst, latestPos{})
+
+	case txt == "/mydata":
+		b.HandleMyData(userID)
+
+	case txt == "/deleteme":
+		b.HandleDeleteMe(userID)
+
+	case txt == "/unread":
+		_ = ingest.FetchAndStoreForUser(b.store, userID)
+		b.ShowUnread(userID, nil)
+
+	case strings.HasPrefix(txt, "/search "):
+		b.HandleSearch(userID, strings.TrimPrefix(txt, "/search "))
+
+	case txt == "/search":
+		b.SendMessage(userID, searchUsage)
 
 	case txt == "/mysources":
-		b.ShowSourcesMenu(userID)
+		b.ShowSourcesMenu(userID, nil)
+
+	case strings.HasPrefix(txt, "/watch "):
+		b.HandleWatch(userID, strings.TrimSpace(strings.TrimPrefix(txt, "/watch ")))
+
+	case strings.HasPrefix(txt, "/unwatch "):
+		b.HandleUnwatch(userID, strings.TrimSpace(strings.TrimPrefix(txt, "/unwatch ")))
+
+	case txt == "/watchlist" || txt == "/watch" || txt == "/unwatch":
+		b.ShowWatchlist(userID)
+
+	case strings.HasPrefix(txt, "/quote "):
+		b.HandleQuote(userID, strings.TrimSpace(strings.TrimPrefix(txt, "/quote ")))
+
+	case txt == "/quote":
+		b.SendMessage(userID, "⚠️ Формат: /quote SBER")
+
+	case strings.HasPrefix(txt, "/alert "):
+		b.HandleAlert(userID, strings.TrimPrefix(txt, "/alert "))
+
+	case txt == "/alerts" || txt == "/alert":
+		b.ShowAlerts(userID, nil)
+
+	case txt == "/calendar":
+		b.ShowCalendar(userID)
+
+	case strings.HasPrefix(txt, "/calsub "):
+		b.HandleCalendarSubscribe(userID, strings.TrimPrefix(txt, "/calsub "))
+
+	case strings.HasPrefix(txt, "/calunsub "):
+		b.HandleCalendarUnsubscribe(userID, strings.TrimPrefix(txt, "/calunsub "))
+
+	case txt == "/timezone" || strings.HasPrefix(txt, "/timezone "):
+		b.HandleTimezone(userID, strings.TrimSpace(strings.TrimPrefix(txt, "/timezone")))
+
+	case txt == "/importance":
+		threshold, _ := b.store.Prefs.GetImportanceThreshold(userID)
+		b.SendMessage(userID, fmt.Sprintf("Порог важности: %d\n"+
+			"Новости с важностью ниже порога приходят в дайджесте по расписанию /autopost.\n"+
+			"Изменить: /importance 5", threshold))
+
+	case strings.HasPrefix(txt, "/importance "):
+		threshold, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(txt, "/importance ")))
+		if err != nil {
+			b.SendMessage(userID, "⚠️ Формат: /importance 5")
+		} else if err := b.store.Prefs.SetImportanceThreshold(userID, threshold); err != nil {
+			b.SendMessage(userID, "❌ Ошибка сохранения порога")
+		} else {
+			msg := fmt.Sprintf("✅ Мгновенно будут приходить новости с важностью от %d", threshold)
+			if times, _ := b.store.Autopost.GetUserAutopost(userID); len(times) == 0 {
+				msg += "\n⚠️ Время дайджеста не задано — настройте его через /autopost"
+			}
+			b.SendMessage(userID, msg)
+		}
+
+	case txt == "/showprice on" || txt == "/showprice off":
+		show := txt == "/showprice on"
+		if err := b.store.Prefs.SetShowPrice(userID, show); err != nil {
+			b.SendMessage(userID, "❌ Ошибка сохранения настройки")
+		} else if show {
+			b.SendMessage(userID, "✅ Цена будет показываться у новостей с тикерами")
+		} else {
+			b.SendMessage(userID, "✅ Строка с ценой отключена")
+		}
+
+	case txt == "/sentiment":
+		filter, _ := b.store.Prefs.GetSentimentFilter(userID)
+		b.SendMessage(userID, "Фильтр тональности: "+filter+"\n\n"+
+			"/sentiment all – все новости\n"+
+			"/sentiment positive – только сильно позитивные 📈\n"+
+			"/sentiment negative – только сильно негативные 📉\n"+
+			"/sentiment strong – сильно позитивные и негативные")
+
+	case strings.HasPrefix(txt, "/sentiment "):
+		filter := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(txt, "/sentiment ")))
+		if !sentiment.ValidFilter(filter) {
+			b.SendMessage(userID, "⚠️ Допустимые значения: "+strings.Join(sentiment.Filters, ", "))
+		} else if err := b.store.Prefs.SetSentimentFilter(userID, filter); err != nil {
+			b.SendMessage(userID, "❌ Ошибка сохранения фильтра")
+		} else {
+			b.SendMessage(userID, "✅ Фильтр тональности: "+filter)
+		}
 
 	case txt == "/addsource" && b.IsAdmin(userID):
 		b.SendMessage(userID, "Введите URL источника для добавления:")
@@ -148,16 +286,10 @@ func (b *Bot) HandleMessage(m *tb.Message) {
 		b.pending[userID] = "removesource"
 
 	case txt == "/listsources" && b.IsAdmin(userID):
-		sources := storage.MustGetAllSources(b.db)
-		if len(sources) == 0 {
-			b.SendMessage(userID, "⚠️ В базе нет источников")
-		} else {
-			out := "📑 Источники:\n"
-			for i, s := range sources {
-				out += fmt.Sprintf("%d. %s\n", i+1, s)
-			}
-			b.SendMessage(userID, out)
-		}
+		b.ListSources(userID)
+
+	case (txt == "/editsource" || strings.HasPrefix(txt, "/editsource ")) && b.IsAdmin(userID):
+		b.HandleEditSource(userID, strings.TrimSpace(strings.TrimPrefix(txt, "/editsource")))
 
 	case txt == "/broadcast" && b.IsAdmin(userID):
 		b.SendMessage(userID, "Введите текст рассылки:")
@@ -166,34 +298,168 @@ func (b *Bot) HandleMessage(m *tb.Message) {
 	// 🔹 Новые команды для settings
 	case strings.HasPrefix(txt, "/setchannel ") && b.IsAdmin(userID):
 		url := strings.TrimSpace(strings.TrimPrefix(txt, "/setchannel "))
-		if url == "" {
-			b.SendMessage(userID, "⚠️ Укажите ссылку на канал")
+		if _, err := b.setSetting(userID, "setchannel", settings.Channel, url); err != nil {
+			b.SendMessage(userID, "⚠️ "+err.Error())
 		} else {
-			_ = storage.SetSetting(b.db, "channel", url)
 			b.SendMessage(userID, "✅ Ссылка на канал обновлена")
 		}
 
 	case strings.HasPrefix(txt, "/setmanual ") && b.IsAdmin(userID):
 		url := strings.TrimSpace(strings.TrimPrefix(txt, "/setmanual "))
-		if url == "" {
-			b.SendMessage(userID, "⚠️ Укажите ссылку на инструкцию")
+		if _, err := b.setSetting(userID, "setmanual", settings.Manual, url); err != nil {
+			b.SendMessage(userID, "⚠️ "+err.Error())
 		} else {
-			_ = storage.SetSetting(b.db, "manual", url)
 			b.SendMessage(userID, "✅ Ссылка на инструкцию обновлена")
 		}
 
-	case txt == "/getsettings" && b.IsAdmin(userID):
-		settings, _ := storage.GetAllSettings(b.db)
-		if len(settings) == 0 {
-			b.SendMessage(userID, "⚠️ Настройки пока пустые")
+	case (txt == "/retention" || strings.HasPrefix(txt, "/retention ")) && b.IsAdmin(userID):
+		b.HandleRetention(userID, strings.TrimPrefix(txt, "/retention"))
+
+	case (txt == "/getsettings" || txt == "/set") && b.IsAdmin(userID):
+		b.ShowSettings(userID, nil)
+
+	case strings.HasPrefix(txt, "/set ") && b.IsAdmin(userID):
+		b.HandleSet(userID, strings.TrimPrefix(txt, "/set "))
+
+	case (txt == "/settinghistory" || strings.HasPrefix(txt, "/settinghistory ")) && b.IsAdmin(userID):
+		b.HandleSettingHistory(userID, strings.TrimSpace(strings.TrimPrefix(txt, "/settinghistory")))
+
+	case strings.HasPrefix(txt, "/addalias ") && b.IsAdmin(userID):
+		parts := strings.Fields(strings.TrimPrefix(txt, "/addalias "))
+		if len(parts) < 2 {
+			b.SendMessage(userID, "⚠️ Формат: /addalias SBER Сбербанк")
+		} else {
+			ticker := parts[0]
+			alias := strings.Join(parts[1:], " ")
+			err := b.store.Tags.AddTagAlias(alias, ticker)
+			b.audit(userID, "addalias", ticker+" "+alias, resultOf(err))
+			if err != nil {
+				b.SendMessage(userID, "❌ Ошибка добавления алиаса")
+			} else {
+				b.SendMessage(userID, fmt.Sprintf("✅ Алиас «%s» → %s", alias, tagger.NormalizeTicker(ticker)))
+			}
+		}
+
+	case strings.HasPrefix(txt, "/removealias ") && b.IsAdmin(userID):
+		alias := strings.TrimSpace(strings.TrimPrefix(txt, "/removealias "))
+		err := b.store.Tags.RemoveTagAlias(alias)
+		b.audit(userID, "removealias", alias, resultOf(err))
+		if err != nil {
+			b.SendMessage(userID, "❌ Ошибка удаления алиаса")
+		} else {
+			b.SendMessage(userID, "✅ Алиас удалён: "+alias)
+		}
+
+	case txt == "/aliases" && b.IsAdmin(userID):
+		aliases, _ := b.store.Tags.GetTagAliases()
+		if len(aliases) == 0 {
+			b.SendMessage(userID, "⚠️ Словарь алиасов пуст (используются встроенные)")
+		} else {
+			keys := make([]string, 0, len(aliases))
+			for a := range aliases {
+				keys = append(keys, a)
+			}
+			sort.Strings(keys)
+			out := "🏷 Алиасы:\n"
+			for _, a := range keys {
+				out += fmt.Sprintf("%s → %s\n", a, aliases[a])
+			}
+			b.SendMessage(userID, out)
+		}
+
+	case strings.HasPrefix(txt, "/addcalendar ") && b.IsAdmin(userID):
+		b.HandleAddCalendar(userID, strings.TrimSpace(strings.TrimPrefix(txt, "/addcalendar ")))
+
+	case strings.HasPrefix(txt, "/removecalendar ") && b.IsAdmin(userID):
+		url := strings.TrimSpace(strings.TrimPrefix(txt, "/removecalendar "))
+		err := b.store.Calendar.RemoveCalendarSource(url)
+		b.audit(userID, "removecalendar", url, resultOf(err))
+		if err != nil {
+			b.SendMessage(userID, "❌ Ошибка удаления календаря")
+		} else {
+			b.SendMessage(userID, "✅ Календарь удалён: "+url)
+		}
+
+	case txt == "/calendars" && b.IsAdmin(userID):
+		urls, _ := b.store.Calendar.GetCalendarSources()
+		if len(urls) == 0 {
+			b.SendMessage(userID, "⚠️ Календарей нет")
+		} else {
+			b.SendMessage(userID, "🗓 Календари:\n"+strings.Join(urls, "\n"))
+		}
+
+	case strings.HasPrefix(txt, "/addrule ") && b.IsAdmin(userID):
+		parts := strings.Fields(strings.TrimPrefix(txt, "/addrule "))
+		if len(parts) < 2 {
+			b.SendMessage(userID, "⚠️ Формат: /addrule 5 ставк")
+			break
+		}
+		weight, err := strconv.Atoi(parts[0])
+		keyword := strings.Join(parts[1:], " ")
+		if err != nil {
+			b.SendMessage(userID, "⚠️ Вес должен быть целым числом")
+		} else if err := b.store.Importance.SetImportanceRule(keyword, weight); err != nil {
+			b.audit(userID, "addrule", parts[0]+" "+keyword, resultOf(err))
+			b.SendMessage(userID, "❌ Ошибка сохранения правила")
+		} else {
+			b.audit(userID, "addrule", parts[0]+" "+keyword, resultOf(nil))
+			b.SendMessage(userID, fmt.Sprintf("✅ Правило: «%s» = %d", keyword, weight))
+		}
+
+	case strings.HasPrefix(txt, "/removerule ") && b.IsAdmin(userID):
+		keyword := strings.TrimSpace(strings.TrimPrefix(txt, "/removerule "))
+		if removed, err := b.store.Importance.RemoveImportanceRule(keyword); err != nil {
+			b.audit(userID, "removerule", keyword, resultOf(err))
+			b.SendMessage(userID, "❌ Ошибка удаления правила")
+		} else if !removed {
+			b.audit(userID, "removerule", keyword, "не найдено")
+			b.SendMessage(userID, "⚠️ Правило не найдено")
+		} else {
+			b.audit(userID, "removerule", keyword, resultOf(nil))
+			b.SendMessage(userID, "✅ Правило удалено: "+keyword)
+		}
+
+	case txt == "/rules" && b.IsAdmin(userID):
+		rules, _ := b.store.Importance.GetImportanceRules()
+		if len(rules) == 0 {
+			b.SendMessage(userID, "⚠️ Правил важности нет. Добавить: /addrule 5 ставк")
 		} else {
-			out := "⚙️ Текущие настройки:\n"
-			for k, v := range settings {
-				out += fmt.Sprintf("%s = %s\n", k, v)
+			out := "⚡️ Правила важности:\n"
+			for _, r := range rules {
+				out += fmt.Sprintf("%+d %s\n", r.Weight, r.Keyword)
 			}
 			b.SendMessage(userID, out)
 		}
 
+	case strings.HasPrefix(txt, "/setpriority ") && b.IsAdmin(userID):
+		parts := strings.Fields(strings.TrimPrefix(txt, "/setpriority "))
+		if len(parts) != 2 {
+			b.SendMessage(userID, "⚠️ Формат: /setpriority <url> 3")
+			break
+		}
+		priority, err := strconv.Atoi(parts[1])
+		if err != nil {
+			b.SendMessage(userID, "⚠️ Приоритет должен быть целым числом")
+		} else if ok, err := b.store.Sources.SetSourcePriority(parts[0], priority); err != nil {
+			b.audit(userID, "setpriority", parts[0]+" "+parts[1], resultOf(err))
+			b.SendMessage(userID, "❌ Ошибка сохранения приоритета")
+		} else if !ok {
+			b.audit(userID, "setpriority", parts[0]+" "+parts[1], "источник не найден")
+			b.SendMessage(userID, "⚠️ Источник не найден")
+		} else {
+			b.audit(userID, "setpriority", parts[0]+" "+parts[1], resultOf(nil))
+			b.SendMessage(userID, fmt.Sprintf("✅ Приоритет источника: %d", priority))
+		}
+
+	case txt == "/stats" && b.IsAdmin(userID):
+		b.HandleStats(userID)
+
+	case (txt == "/user" || strings.HasPrefix(txt, "/user ")) && b.IsAdmin(userID):
+		b.HandleUserLookup(userID, strings.TrimSpace(strings.TrimPrefix(txt, "/user")))
+
+	case (txt == "/audit" || strings.HasPrefix(txt, "/audit ")) && b.IsAdmin(userID):
+		b.HandleAudit(userID, strings.TrimSpace(strings.TrimPrefix(txt, "/audit")))
+
 	default:
 		log.Printf("Сообщение: %s", txt)
 	}
@@ -0,0 +1,181 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Языки сообщений бота. Админские сообщения только на русском
+const (
+	langRU = "ru"
+	langEN = "en"
+)
+
+// Коды языков Telegram, для которых отвечаем по-русски; пустой код — тоже русский
+var russianLangs = map[string]bool{"": true, "ru": true, "uk": true, "be": true, "kk": true}
+
+// Ключи переведённых сообщений
+const (
+	msgStartUser = "start_user"
+	msgHelpUser  = "help_user"
+
+	msgMyDataCaption   = "mydata_caption"
+	msgMyDataError     = "mydata_error"
+	msgDeleteConfirm   = "delete_confirm"
+	msgDeleteButton    = "delete_button"
+	msgCancelButton    = "cancel_button"
+	msgDeleted         = "deleted"
+	msgDeleteCancelled = "delete_cancelled"
+	msgDeleteError     = "delete_error"
+
+	msgSourcesMenu  = "sources_menu"
+	msgSourcesOther = "sources_other"
+	msgSourcesEmpty = "sources_empty"
+	msgSourcesError = "sources_error"
+
+	msgSubscribed     = "subscribed"
+	msgUnsubscribed   = "unsubscribed"
+	msgSubscribeError = "subscribe_error"
+)
+
+var messages = map[string]map[string]string{
+	msgStartUser: {
+		langRU: "👤 Пользователь\nID: %d\nПодписок: %d\nНепрочитанных новостей: %d",
+		langEN: "👤 User\nID: %d\nSubscriptions: %d\nUnread news: %d",
+	},
+	msgHelpUser: {
+		langRU: "Доступные команды:\n" +
+			"/start – информация\n" +
+			"/help – список команд\n" +
+			"/latest – новости\n" +
+			"/unread – непрочитанные новости\n" +
+			"/search <запрос> – поиск по новостям\n" +
+			"/mysources – источники и подписки\n" +
+			"/autopost – авторассылка\n" +
+			"/watch <тикер> – следить за инструментом\n" +
+			"/unwatch <тикер> – перестать следить\n" +
+			"/watchlist – мой watchlist\n" +
+			"/sentiment – фильтр по тональности\n" +
+			"/quote <тикер> – котировка\n" +
+			"/showprice on|off – цена у новостей\n" +
+			"/alert <тикер> > <цена> – ценовой алерт\n" +
+			"/alerts – мои алерты\n" +
+			"/calendar – экономический календарь\n" +
+			"/calsub <категория> [мин] – напоминания о событиях\n" +
+			"/calunsub <категория> – отключить напоминания\n" +
+			"/timezone <пояс> – часовой пояс\n" +
+			"/importance <порог> – порог мгновенных уведомлений\n" +
+			"/mydata – выгрузить мои данные\n" +
+			"/deleteme – удалить мои данные",
+		langEN: "Available commands:\n" +
+			"/start – info\n" +
+			"/help – list of commands\n" +
+			"/latest – news\n" +
+			"/unread – unread news\n" +
+			"/search <query> – search news\n" +
+			"/mysources – sources and subscriptions\n" +
+			"/autopost – digest schedule\n" +
+			"/watch <ticker> – watch an instrument\n" +
+			"/unwatch <ticker> – stop watching\n" +
+			"/watchlist – my watchlist\n" +
+			"/sentiment – sentiment filter\n" +
+			"/quote <ticker> – quote\n" +
+			"/showprice on|off – price next to news\n" +
+			"/alert <ticker> > <price> – price alert\n" +
+			"/alerts – my alerts\n" +
+			"/calendar – economic calendar\n" +
+			"/calsub <category> [min] – event reminders\n" +
+			"/calunsub <category> – disable reminders\n" +
+			"/timezone <zone> – time zone\n" +
+			"/importance <threshold> – instant notification threshold\n" +
+			"/mydata – export my data\n" +
+			"/deleteme – delete my data",
+	},
+	msgMyDataCaption: {
+		langRU: "📦 Всё, что бот хранит о вас",
+		langEN: "📦 Everything the bot stores about you",
+	},
+	msgMyDataError: {
+		langRU: "❌ Не удалось выгрузить данные, попробуйте позже",
+		langEN: "❌ Could not export your data, please try again later",
+	},
+	msgDeleteConfirm: {
+		langRU: "⚠️ Будут удалены профиль, подписки, watchlist, расписание дайджеста, алерты, " +
+			"настройки и история прочитанного. Отменить это нельзя.\n\nУдалить?",
+		langEN: "⚠️ Your profile, subscriptions, watchlist, digest schedule, alerts, " +
+			"preferences and read history will be deleted. This cannot be undone.\n\nDelete?",
+	},
+	msgDeleteButton: {
+		langRU: "🗑 Удалить мои данные",
+		langEN: "🗑 Delete my data",
+	},
+	msgCancelButton: {
+		langRU: "Отмена",
+		langEN: "Cancel",
+	},
+	msgDeleted: {
+		langRU: "✅ Ваши данные удалены. Если напишете боту снова, он начнёт с чистого листа.",
+		langEN: "✅ Your data has been deleted. If you message the bot again, it will start from scratch.",
+	},
+	msgDeleteCancelled: {
+		langRU: "Удаление отменено",
+		langEN: "Deletion cancelled",
+	},
+	msgDeleteError: {
+		langRU: "❌ Ошибка удаления",
+		langEN: "❌ Deletion failed",
+	},
+	msgSourcesMenu: {
+		langRU: "🔔 Источники новостей, ✅ — ваши подписки.\nНажмите на источник, чтобы подписаться или отписаться.",
+		langEN: "🔔 News sources, ✅ — your subscriptions.\nTap a source to subscribe or unsubscribe.",
+	},
+	msgSourcesOther: {
+		langRU: "Другое",
+		langEN: "Other",
+	},
+	msgSourcesEmpty: {
+		langRU: "⚠️ Источников пока нет",
+		langEN: "⚠️ No sources yet",
+	},
+	msgSourcesError: {
+		langRU: "❌ Ошибка загрузки источников",
+		langEN: "❌ Failed to load sources",
+	},
+	msgSubscribed: {
+		langRU: "✅ Подписка оформлена",
+		langEN: "✅ Subscribed",
+	},
+	msgUnsubscribed: {
+		langRU: "Подписка отменена",
+		langEN: "Unsubscribed",
+	},
+	msgSubscribeError: {
+		langRU: "❌ Ошибка изменения подписки",
+		langEN: "❌ Failed to update the subscription",
+	},
+}
+
+// langOf выбирает язык сообщений по language_code из Telegram
+func langOf(code string) string {
+	// en-US, pt-br: важна только основная часть
+	code, _, _ = strings.Cut(strings.ToLower(code), "-")
+	if russianLangs[code] {
+		return langRU
+	}
+	return langEN
+}
+
+// userLang — язык сообщений пользователя по его профилю
+func (b *Bot) userLang(userID int64) string {
+	p, _, _ := b.store.Users.GetUser(userID)
+	return langOf(p.LanguageCode)
+}
+
+// text возвращает сообщение key на языке пользователя
+func (b *Bot) text(userID int64, key string, args ...interface{}) string {
+	msg := messages[key][b.userLang(userID)]
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
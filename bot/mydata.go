@@ -0,0 +1,172 @@
+package bot
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"time"
+
+	tb "gopkg.in/telebot.v3"
+)
+
+// userExport — данные пользователя для /mydata
+type userExport struct {
+	ExportedAt            time.Time         `json:"exported_at"`
+	Profile               profileExport     `json:"profile"`
+	Subscriptions         []string          `json:"subscriptions"`
+	Watchlist             []string          `json:"watchlist"`
+	Autopost              []autopostExport  `json:"autopost"`
+	Preferences           preferencesExport `json:"preferences"`
+	PriceAlerts           []alertExport     `json:"price_alerts"`
+	CalendarSubscriptions map[string]int    `json:"calendar_subscriptions"`
+	ReadNews              []string          `json:"read_news"`
+}
+
+type profileExport struct {
+	ID           int64      `json:"id"`
+	Username     string     `json:"username,omitempty"`
+	FirstName    string     `json:"first_name,omitempty"`
+	LastName     string     `json:"last_name,omitempty"`
+	LanguageCode string     `json:"language_code,omitempty"`
+	JoinedAt     *time.Time `json:"joined_at,omitempty"`
+	LastSeenAt   *time.Time `json:"last_seen_at,omitempty"`
+	Status       string     `json:"status"`
+}
+
+type autopostExport struct {
+	Time     string `json:"time"`
+	Days     string `json:"days"`
+	Timezone string `json:"timezone"`
+	Enabled  bool   `json:"enabled"`
+}
+
+type alertExport struct {
+	Ticker    string    `json:"ticker"`
+	Op        string    `json:"op"`
+	Level     float64   `json:"level"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type preferencesExport struct {
+	Timezone            string `json:"timezone"`
+	SentimentFilter     string `json:"sentiment_filter"`
+	ShowPrice           bool   `json:"show_price"`
+	ImportanceThreshold int    `json:"importance_threshold"`
+}
+
+// exportUser собирает всё, что бот хранит о пользователе
+func (b *Bot) exportUser(userID int64) (userExport, error) {
+	e := userExport{ExportedAt: time.Now().UTC()}
+	p, _, err := b.store.Users.GetUser(userID)
+	if err != nil {
+		return e, err
+	}
+	e.Profile = profileExport{
+		ID:           userID,
+		Username:     p.Username,
+		FirstName:    p.FirstName,
+		LastName:     p.LastName,
+		LanguageCode: p.LanguageCode,
+		Status:       p.Status,
+	}
+	if !p.JoinedAt.IsZero() {
+		joined := p.JoinedAt.UTC()
+		e.Profile.JoinedAt = &joined
+	}
+	if !p.LastSeenAt.IsZero() {
+		seen := p.LastSeenAt.UTC()
+		e.Profile.LastSeenAt = &seen
+	}
+
+	if e.Subscriptions, err = b.store.Subscriptions.GetUserSubscriptions(userID); err != nil {
+		return e, err
+	}
+	if e.Watchlist, err = b.store.Watchlist.GetWatchlist(userID); err != nil {
+		return e, err
+	}
+	schedules, err := b.store.Autopost.GetUserAutopost(userID)
+	if err != nil {
+		return e, err
+	}
+	for _, s := range schedules {
+		e.Autopost = append(e.Autopost, autopostExport{
+			Time:     s.Clock(),
+			Days:     formatWeekdays(s.Weekdays),
+			Timezone: s.Timezone,
+			Enabled:  s.Enabled,
+		})
+	}
+	e.Preferences.Timezone, _ = b.store.Prefs.GetTimezone(userID)
+	e.Preferences.SentimentFilter, _ = b.store.Prefs.GetSentimentFilter(userID)
+	e.Preferences.ShowPrice, _ = b.store.Prefs.GetShowPrice(userID)
+	e.Preferences.ImportanceThreshold, _ = b.store.Prefs.GetImportanceThreshold(userID)
+	alerts, err := b.store.Alerts.GetUserPriceAlerts(userID)
+	if err != nil {
+		return e, err
+	}
+	for _, a := range alerts {
+		e.PriceAlerts = append(e.PriceAlerts, alertExport{Ticker: a.Ticker, Op: a.Op, Level: a.Level, CreatedAt: a.CreatedAt.UTC()})
+	}
+	if e.CalendarSubscriptions, err = b.store.Calendar.GetCalendarSubscriptions(userID); err != nil {
+		return e, err
+	}
+	if e.ReadNews, err = b.store.Reads.GetReadNews(userID); err != nil {
+		return e, err
+	}
+	return e, nil
+}
+
+// HandleMyData — /mydata: все данные пользователя JSON-файлом
+func (b *Bot) HandleMyData(userID int64) {
+	e, err := b.exportUser(userID)
+	if err != nil {
+		log.Printf("Ошибка выгрузки данных %d: %v", userID, err)
+		b.SendMessage(userID, b.text(userID, msgMyDataError))
+		return
+	}
+	data, _ := json.MarshalIndent(e, "", "  ")
+	doc := &tb.Document{
+		File:     tb.FromReader(bytes.NewReader(data)),
+		FileName: "mydata.json",
+		MIME:     "application/json",
+		Caption:  b.text(userID, msgMyDataCaption),
+	}
+	if _, err := b.bot.Send(tb.ChatID(userID), doc); err != nil {
+		log.Printf("Ошибка отправки данных %d: %v", userID, err)
+	}
+}
+
+// HandleDeleteMe — /deleteme: удаление данных после подтверждения кнопкой
+func (b *Bot) HandleDeleteMe(userID int64) {
+	confirm, cancel := b.btnDeleteMe, b.btnDeleteCancel
+	confirm.Text = b.text(userID, msgDeleteButton)
+	cancel.Text = b.text(userID, msgCancelButton)
+	markup := &tb.ReplyMarkup{InlineKeyboard: [][]tb.InlineButton{{confirm}, {cancel}}}
+	_, _ = b.bot.Send(tb.ChatID(userID), b.text(userID, msgDeleteConfirm), markup)
+}
+
+// deleteMe удаляет пользователя (кнопка подтверждения)
+func (b *Bot) deleteMe(c tb.Context) error {
+	userID := c.Sender().ID
+	// язык нужен до удаления профиля
+	lang := b.userLang(userID)
+	if err := b.store.Users.DeleteUser(userID); err != nil {
+		log.Printf("Ошибка удаления пользователя %d: %v", userID, err)
+		return c.Respond(&tb.CallbackResponse{Text: messages[msgDeleteError][lang]})
+	}
+	delete(b.pending, userID)
+	delete(b.latestTotals, userID)
+	delete(b.latestExpanded, userID)
+	delete(b.searchQuery, userID)
+	delete(b.searchPage, userID)
+	delete(b.autopostDays, userID)
+	log.Printf("Пользователь %d удалил свои данные", userID)
+	_ = c.Edit(messages[msgDeleted][lang])
+	return c.Respond()
+}
+
+// cancelDeleteMe отменяет удаление (кнопка)
+func (b *Bot) cancelDeleteMe(c tb.Context) error {
+	_ = c.Edit(b.text(c.Sender().ID, msgDeleteCancelled))
+	return c.Respond()
+}
@@ -2,54 +2,148 @@ package bot
 
 import (
 	"fmt"
+	"html"
+	"strings"
+	"time"
 
+	"github.com/FFFFFFFFFFj/trade-news-bot/sentiment"
 	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+	"github.com/FFFFFFFFFFj/trade-news-bot/tagger"
 	tb "gopkg.in/telebot.v3"
 )
 
-func (b *Bot) ShowLatestNews(chatID int64, c tb.Context) {
-	page := b.latestPage[chatID]
+// Новостей на странице /latest
+const latestPageSize = 4
+
+// Сколько действует закэшированное число новостей для счётчика страниц
+const latestTotalTTL = 5 * time.Minute
+
+// latestPos — страница /latest: её номер и курсор. Передаётся в callback data
+// кнопок, поэтому листание не зависит от состояния бота
+type latestPos struct {
+	Page   int
+	Cursor storage.NewsCursor
+}
+
+// latestTotal — закэшированное число новостей пользователя
+type latestTotal struct {
+	count int
+	at    time.Time
+}
+
+// encode упаковывает позицию в callback data: page|unix_micro|id
+func (p latestPos) encode() string {
+	return fmt.Sprintf("%d|%d|%d", p.Page, p.Cursor.PubDate.UnixMicro(), p.Cursor.ID)
+}
+
+func parseLatestPos(data string) (latestPos, error) {
+	var p latestPos
+	var micro int64
+	if _, err := fmt.Sscanf(strings.ReplaceAll(data, "|", " "), "%d %d %d", &p.Page, &micro, &p.Cursor.ID); err != nil {
+		return p, err
+	}
+	p.Cursor.PubDate = time.UnixMicro(micro).UTC()
+	return p, nil
+}
+
+// latestTotalCount возвращает число новостей пользователя для счётчика страниц.
+// COUNT по всей таблице дорогой, поэтому при листании используется кэш
+func (b *Bot) latestTotalCount(chatID int64, refresh bool) int {
+	cached, ok := b.latestTotals[chatID]
+	if ok && !refresh && time.Since(cached.at) < latestTotalTTL {
+		return cached.count
+	}
+	count, err := b.store.News.CountLatestNewsForUser(chatID)
+	if err != nil {
+		return cached.count
+	}
+	b.latestTotals[chatID] = latestTotal{count: count, at: time.Now()}
+	return count
+}
+
+// ShowLatestNews показывает страницу /latest относительно позиции pos.
+// c == nil — новый вызов команды: сообщение отправляется заново, счётчик обновляется
+func (b *Bot) ShowLatestNews(chatID int64, c tb.Context, dir storage.PageDirection, pos latestPos) {
+	total := b.latestTotalCount(chatID, c == nil)
+	totalPages := (total + latestPageSize - 1) / latestPageSize
+
+	news, _ := b.store.News.GetLatestNewsForUser(chatID, pos.Cursor, dir, latestPageSize)
+	page := pos.Page
+	switch dir {
+	case storage.PageFirst:
+		page = 1
+	case storage.PageLast:
+		page = totalPages
+	case storage.PageBefore:
+		// до начала осталось меньше страницы — показываем первую целиком
+		if len(news) < latestPageSize {
+			news, _ = b.store.News.GetLatestNewsForUser(chatID, storage.NewsCursor{}, storage.PageFirst, latestPageSize)
+			page = 1
+		}
+	}
 	if page < 1 {
 		page = 1
 	}
-	pageSize := 4
+	// с момента подсчёта могли появиться новости
+	if totalPages < page {
+		totalPages = page
+	}
 
-	news, _ := storage.GetLatestNewsPageForUser(b.db, chatID, page, pageSize)
 	if len(news) == 0 {
 		if c != nil {
-			_ = c.Edit("Сегодня новостей нет.")
+			_ = c.Edit("Новостей нет.")
 		} else {
-			b.SendMessage(chatID, "Сегодня новостей нет.")
+			b.SendMessage(chatID, "Новостей нет.")
 		}
 		return
 	}
 
-	text := "📰 Новости за сегодня:\n\n"
+	text := "📰 Последние новости:\n\n"
+	expanded := b.latestExpanded[chatID]
+	showPrice, _ := b.store.Prefs.GetShowPrice(chatID)
+	hasSummary := false
+	names := b.sourceNames()
 	for _, n := range news {
-		text += fmt.Sprintf("• <b>%s</b>\n%s\n\n", n.Title, n.Link)
-	}
-
-	// считаем страницы
-	totalCount, _ := storage.GetTodayNewsCountForUser(b.db, chatID)
-	totalPages := (totalCount + pageSize - 1) / pageSize
-	if totalPages < 1 {
-		totalPages = 1
+		text += fmt.Sprintf("• %s <b>%s</b>\n", sentiment.Emoji(n.Sentiment), n.Title)
+		text += "🗞 " + html.EscapeString(sourceLabel(names, n.Source)) + "\n"
+		if n.Summary != "" {
+			hasSummary = true
+			if expanded {
+				text += fmt.Sprintf("<i>%s</i>\n", html.EscapeString(n.Summary))
+			}
+		}
+		if len(n.Tags) > 0 {
+			text += tagger.Hashtags(n.Tags) + "\n"
+			if showPrice {
+				if price := b.priceLine(n.Tags); price != "" {
+					text += price + "\n"
+				}
+			}
+		}
+		text += n.Link + "\n\n"
 	}
 
 	text += fmt.Sprintf("📄 Страница %d/%d", page, totalPages)
+	b.markRead(chatID, news)
+
+	// формируем кнопки: «назад» и «вперёд» несут курсор первой и последней новости
+	current := latestPos{Page: page, Cursor: news[0].Cursor()}
+	prev, next := b.btnPrev, b.btnNext
+	prev.Data = latestPos{Page: page - 1, Cursor: news[0].Cursor()}.encode()
+	next.Data = latestPos{Page: page + 1, Cursor: news[len(news)-1].Cursor()}.encode()
 
-	// формируем кнопки
 	btns := [][]tb.InlineButton{}
-	row := []tb.InlineButton{}
-	if page > 1 {
-		row = append(row, b.btnFirst, b.btnPrev)
-	}
-	if page < totalPages {
-		row = append(row, b.btnNext, b.btnLast)
-	}
-	if len(row) > 0 {
+	if row := navRow(page, totalPages, b.btnFirst, prev, next, b.btnLast); len(row) > 0 {
 		btns = append(btns, row)
 	}
+	if hasSummary {
+		toggle := b.btnMore
+		if expanded {
+			toggle = b.btnLess
+		}
+		toggle.Data = current.encode()
+		btns = append(btns, []tb.InlineButton{toggle})
+	}
 	markup := &tb.ReplyMarkup{InlineKeyboard: btns}
 
 	// если это вызов из кнопки → редактируем
@@ -63,3 +157,32 @@ func (b *Bot) ShowLatestNews(chatID int64, c tb.Context) {
 		)
 	}
 }
+
+// navRow — кнопки перехода между страницами списка
+func navRow(page, totalPages int, first, prev, next, last tb.InlineButton) []tb.InlineButton {
+	row := []tb.InlineButton{}
+	if page > 1 {
+		row = append(row, first, prev)
+	}
+	if page < totalPages {
+		row = append(row, next, last)
+	}
+	return row
+}
+
+// formatPush форматирует новость для push-уведомления; source — название источника,
+// price — необязательная строка с ценами по тегам
+func formatPush(n storage.NewsItem, source, price string) string {
+	text := "📰 " + sentiment.Emoji(n.Sentiment) + " " + n.Title + "\n"
+	text += "🗞 " + source + "\n"
+	if n.Summary != "" {
+		text += "\n" + n.Summary + "\n\n"
+	}
+	if len(n.Tags) > 0 {
+		text += tagger.Hashtags(n.Tags) + "\n"
+	}
+	if price != "" {
+		text += price + "\n"
+	}
+	return text + n.Link
+}
@@ -0,0 +1,116 @@
+package bot
+
+import (
+	"log"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/sentiment"
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+const (
+	// как часто проверять outbox и сколько доставок брать за раз
+	outboxInterval = 5 * time.Second
+	outboxBatch    = 50
+	// на сколько резервировать доставку; после падения отправителя её заберут снова
+	outboxLease = 2 * time.Minute
+	// после стольких неудачных попыток доставка считается проваленной
+	outboxMaxAttempts = 8
+	// пауза перед повтором удваивается с каждой попыткой
+	outboxBaseBackoff = 30 * time.Second
+	outboxMaxBackoff  = time.Hour
+)
+
+// StartOutboxSender отправляет новости из outbox: мгновенно или в дайджест,
+// с учётом фильтра тональности и порога важности пользователя
+func (b *Bot) StartOutboxSender() {
+	ticker := time.NewTicker(outboxInterval)
+	for range ticker.C {
+		// полная порция — в очереди может быть ещё, берём следующую сразу
+		for b.sendOutbox() == outboxBatch {
+		}
+	}
+}
+
+// sendOutbox обрабатывает одну порцию доставок и возвращает её размер
+func (b *Bot) sendOutbox() int {
+	deliveries, err := b.store.Outbox.ClaimDeliveries(outboxBatch, outboxLease)
+	if err != nil {
+		log.Printf("Ошибка выборки outbox: %v", err)
+		return 0
+	}
+	if len(deliveries) == 0 {
+		return 0
+	}
+
+	filters, _ := b.store.Prefs.GetSentimentFilters()
+	thresholds, _ := b.store.Prefs.GetImportanceThresholds()
+	showPrice, _ := b.store.Prefs.GetShowPriceUsers()
+	prices := make(map[string]string)
+	names := b.sourceNames()
+	for _, d := range deliveries {
+		status, err := b.deliver(d, filters, thresholds, showPrice, prices, names)
+		if inactiveStatus(err) != "" {
+			// доставку закрыл DeactivateUser вместе с остальными
+			continue
+		}
+		if err != nil {
+			b.retryDelivery(d, err)
+			continue
+		}
+		if err := b.store.Outbox.CompleteDelivery(d.ID, status, ""); err != nil {
+			log.Printf("Ошибка записи итога доставки: %v", err)
+		}
+	}
+	return len(deliveries)
+}
+
+// deliver отправляет новость пользователю или откладывает её до дайджеста
+func (b *Bot) deliver(d storage.Delivery, filters map[int64]string, thresholds map[int64]int, showPrice map[int64]bool, prices, names map[string]string) (string, error) {
+	n := d.News
+	// уже видел в /latest или /unread
+	if d.Read || !sentiment.Match(filters[d.UserID], n.Sentiment) {
+		return storage.DeliverySkipped, nil
+	}
+	// менее важные новости ждут следующего дайджеста
+	if n.Importance < thresholds[d.UserID] {
+		if err := b.store.News.DeferNews(d.UserID, n.Link); err != nil {
+			return "", err
+		}
+		return storage.DeliveryDeferred, nil
+	}
+
+	price := ""
+	if showPrice[d.UserID] {
+		if _, ok := prices[n.Link]; !ok {
+			prices[n.Link] = b.priceLine(n.Tags)
+		}
+		price = prices[n.Link]
+	}
+	if _, err := b.send(d.UserID, formatPush(n, sourceLabel(names, n.Source), price)); err != nil {
+		return "", err
+	}
+	b.markRead(d.UserID, []storage.NewsItem{n})
+	return storage.DeliverySent, nil
+}
+
+// retryDelivery откладывает повтор с экспоненциальной паузой
+// или, если попытки исчерпаны, закрывает доставку с ошибкой
+func (b *Bot) retryDelivery(d storage.Delivery, sendErr error) {
+	attempts := d.Attempts + 1
+	if attempts >= outboxMaxAttempts {
+		log.Printf("Доставка %d пользователю %d не удалась: %v", d.ID, d.UserID, sendErr)
+		if err := b.store.Outbox.CompleteDelivery(d.ID, storage.DeliveryFailed, sendErr.Error()); err != nil {
+			log.Printf("Ошибка записи итога доставки: %v", err)
+		}
+		return
+	}
+
+	backoff := outboxBaseBackoff << (attempts - 1)
+	if backoff > outboxMaxBackoff {
+		backoff = outboxMaxBackoff
+	}
+	if err := b.store.Outbox.RetryDelivery(d.ID, time.Now().Add(backoff), sendErr.Error()); err != nil {
+		log.Printf("Ошибка записи повтора доставки: %v", err)
+	}
+}
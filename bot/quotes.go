@@ -0,0 +1,55 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/quotes"
+)
+
+// SetQuoteProvider подключает источник котировок для /quote и строки с ценой
+func (b *Bot) SetQuoteProvider(p quotes.QuoteProvider) {
+	b.quotes = p
+}
+
+func (b *Bot) HandleQuote(userID int64, arg string) {
+	if b.quotes == nil {
+		b.SendMessage(userID, "⚠️ Котировки не настроены")
+		return
+	}
+	ticker := b.resolveTicker(arg)
+	if ticker == "" {
+		b.SendMessage(userID, "⚠️ Формат: /quote SBER")
+		return
+	}
+
+	q, err := b.quotes.Quote(ticker)
+	if errors.Is(err, quotes.ErrNotFound) {
+		b.SendMessage(userID, fmt.Sprintf("⚠️ Нет котировки для %s", ticker))
+		return
+	}
+	if err != nil {
+		b.SendMessage(userID, "❌ Ошибка получения котировки")
+		return
+	}
+	b.SendMessage(userID, fmt.Sprintf("%s %s\n🕒 %s", q.Emoji(), q, q.Time.In(moscow).Format("15:04 02.01.2006")))
+}
+
+// priceLine возвращает строку с текущими ценами по тегам новости
+func (b *Bot) priceLine(tags []string) string {
+	if b.quotes == nil {
+		return ""
+	}
+	line := ""
+	for _, t := range tags {
+		q, err := b.quotes.Quote(t)
+		if err != nil {
+			continue
+		}
+		if line != "" {
+			line += " · "
+		}
+		line += q.Emoji() + " " + q.String()
+	}
+	return line
+}
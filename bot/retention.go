@@ -0,0 +1,139 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/settings"
+)
+
+// Час (по Москве), в который запускается очистка
+const retentionHour = 4
+
+const retentionUsage = "⚠️ Формат:\n" +
+	"/retention <дней> [archive|delete|dryrun] – хранить новости N дней\n" +
+	"/retention off – хранить всегда\n" +
+	"/retention check – что будет удалено сейчас\n" +
+	"/retention run – выполнить очистку сейчас"
+
+// retentionSettings возвращает срок хранения в днях (0 — очистка выключена) и режим
+func (b *Bot) retentionSettings() (int, string) {
+	values := b.settingValues()
+	return values.Int(settings.RetentionDays), values.String(settings.RetentionMode)
+}
+
+// StartRetentionJob раз в сутки удаляет или архивирует старые новости.
+// В режиме dryrun админы получают отчёт, ничего не удаляется
+func (b *Bot) StartRetentionJob() {
+	ticker := time.NewTicker(time.Hour)
+	for range ticker.C {
+		if time.Now().In(moscow).Hour() != retentionHour {
+			continue
+		}
+		days, mode := b.retentionSettings()
+		if days <= 0 {
+			continue
+		}
+		report, err := b.runRetention(days, mode)
+		if err != nil {
+			log.Printf("Ошибка очистки новостей: %v", err)
+			continue
+		}
+		log.Print(report)
+		if mode == settings.RetentionDryRun {
+			for id := range AdminIDs {
+				b.SendMessage(id, report)
+			}
+		}
+	}
+}
+
+// runRetention выполняет очистку и возвращает отчёт для админа
+func (b *Bot) runRetention(days int, mode string) (string, error) {
+	before := time.Now().AddDate(0, 0, -days)
+	dryRun := mode == settings.RetentionDryRun
+	stats, err := b.store.Retention.PruneNews(before, mode == settings.RetentionArchive, dryRun)
+	if err != nil {
+		return "", err
+	}
+
+	if dryRun {
+		return fmt.Sprintf("🧹 Проверка хранения (%d дн.): будет удалено новостей: %d, отметок о прочтении: %d",
+			days, stats.News, stats.ReadMarkers), nil
+	}
+	if stats.News > 0 {
+		if err := b.store.Retention.Analyze(); err != nil {
+			log.Printf("Ошибка обновления статистики: %v", err)
+		}
+	}
+	action := "удалено"
+	if mode == settings.RetentionArchive {
+		action = "перенесено в архив"
+	}
+	return fmt.Sprintf("🧹 Очистка (%d дн.): новостей %s: %d, отметок о прочтении удалено: %d",
+		days, action, stats.News, stats.ReadMarkers), nil
+}
+
+// HandleRetention — /retention для админов
+func (b *Bot) HandleRetention(userID int64, arg string) {
+	days, mode := b.retentionSettings()
+	parts := strings.Fields(arg)
+
+	if len(parts) == 0 {
+		status := "хранить всегда"
+		if days > 0 {
+			status = fmt.Sprintf("%d дн., режим %s", days, mode)
+		}
+		b.SendMessage(userID, "🗄 Хранение новостей: "+status+"\n\n"+retentionUsage)
+		return
+	}
+
+	switch parts[0] {
+	case "off":
+		if _, err := b.setSetting(userID, "retention", settings.RetentionDays, "0"); err != nil {
+			b.SendMessage(userID, "⚠️ "+err.Error())
+			return
+		}
+		b.SendMessage(userID, "✅ Очистка новостей выключена")
+
+	case "check", "run":
+		if days <= 0 {
+			b.SendMessage(userID, "⚠️ Срок хранения не задан: /retention <дней>")
+			return
+		}
+		if parts[0] == "check" {
+			mode = settings.RetentionDryRun
+		}
+		report, err := b.runRetention(days, mode)
+		if mode != settings.RetentionDryRun {
+			b.audit(userID, "retention", "run", resultOf(err))
+		}
+		if err != nil {
+			log.Printf("Ошибка очистки новостей: %v", err)
+			b.SendMessage(userID, "❌ Ошибка очистки")
+			return
+		}
+		b.SendMessage(userID, report)
+
+	default:
+		if n, err := strconv.Atoi(parts[0]); err != nil || n < 1 {
+			b.SendMessage(userID, retentionUsage)
+			return
+		}
+		if len(parts) > 1 {
+			if _, err := b.setSetting(userID, "retention", settings.RetentionMode, parts[1]); err != nil {
+				b.SendMessage(userID, "⚠️ "+err.Error()+"\n\n"+retentionUsage)
+				return
+			}
+		}
+		if _, err := b.setSetting(userID, "retention", settings.RetentionDays, parts[0]); err != nil {
+			b.SendMessage(userID, "⚠️ "+err.Error())
+			return
+		}
+		days, mode = b.retentionSettings()
+		b.SendMessage(userID, fmt.Sprintf("✅ Новости хранятся %d дн., режим %s. Проверить: /retention check", days, mode))
+	}
+}
@@ -0,0 +1,145 @@
+package bot
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/sentiment"
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+	tb "gopkg.in/telebot.v3"
+)
+
+// Результатов поиска на странице
+const searchPageSize = 5
+
+const searchUsage = "⚠️ Формат: /search <запрос> [source:<часть адреса>] [from:ДД.ММ.ГГГГ] [to:ДД.ММ.ГГГГ]\n" +
+	"Например: /search сбербанк дивиденды source:rbc from:01.03.2024"
+
+// Форматы дат в фильтрах from:/to:
+var searchDateLayouts = []string{"02.01.2006", "2006-01-02"}
+
+// parseSearchQuery разбирает запрос /search: обычные слова идут в полнотекстовый
+// поиск, source:, from: и to: — фильтры. Даты — в часовом поясе пользователя,
+// to: включает весь указанный день
+func parseSearchQuery(input string, loc *time.Location) (storage.SearchQuery, error) {
+	var q storage.SearchQuery
+	var words []string
+	for _, f := range strings.Fields(input) {
+		key, value, ok := strings.Cut(f, ":")
+		if !ok || value == "" {
+			words = append(words, f)
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "source":
+			q.Source = value
+		case "from", "to":
+			d, err := parseSearchDate(value, loc)
+			if err != nil {
+				return q, fmt.Errorf("неверная дата %s", value)
+			}
+			if strings.ToLower(key) == "from" {
+				q.From = d
+			} else {
+				q.To = d.AddDate(0, 0, 1)
+			}
+		default:
+			words = append(words, f)
+		}
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return q, fmt.Errorf("дата from: позже to:")
+	}
+	q.Text = strings.Join(words, " ")
+	return q, nil
+}
+
+func parseSearchDate(s string, loc *time.Location) (time.Time, error) {
+	var err error
+	for _, layout := range searchDateLayouts {
+		var t time.Time
+		if t, err = time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+func (b *Bot) HandleSearch(userID int64, arg string) {
+	q, err := parseSearchQuery(arg, b.userLocation(userID))
+	if err != nil {
+		b.SendMessage(userID, "⚠️ "+err.Error()+"\n\n"+searchUsage)
+		return
+	}
+	if q.Text == "" {
+		b.SendMessage(userID, searchUsage)
+		return
+	}
+	b.searchQuery[userID] = q
+	b.searchPage[userID] = 1
+	b.ShowSearchResults(userID, nil)
+}
+
+// ShowSearchResults показывает текущую страницу последнего поиска пользователя
+func (b *Bot) ShowSearchResults(chatID int64, c tb.Context) {
+	q, ok := b.searchQuery[chatID]
+	if !ok {
+		b.SendMessage(chatID, searchUsage)
+		return
+	}
+
+	total, err := b.store.News.CountSearchNews(q)
+	if err != nil {
+		b.SendMessage(chatID, "❌ Ошибка поиска")
+		return
+	}
+	if total == 0 {
+		b.SendMessage(chatID, "🔍 Ничего не найдено по запросу «"+q.Text+"»")
+		return
+	}
+
+	totalPages := (total + searchPageSize - 1) / searchPageSize
+	page := b.searchPage[chatID]
+	if page < 1 {
+		page = 1
+	}
+	if page > totalPages {
+		page = totalPages
+	}
+	b.searchPage[chatID] = page
+
+	news, _ := b.store.News.SearchNews(q, page, searchPageSize)
+	loc := b.userLocation(chatID)
+
+	text := fmt.Sprintf("🔍 «%s» — найдено: %d\n\n", html.EscapeString(q.Text), total)
+	names := b.sourceNames()
+	for _, n := range news {
+		text += fmt.Sprintf("• %s <b>%s</b>\n", sentiment.Emoji(n.Sentiment), html.EscapeString(n.Title))
+		text += fmt.Sprintf("🗓 %s · %s\n", n.PubDate.In(loc).Format("02.01.2006 15:04"), html.EscapeString(sourceLabel(names, n.Source)))
+		text += n.Link + "\n\n"
+	}
+	text += fmt.Sprintf("📄 Страница %d/%d", page, totalPages)
+
+	var btns [][]tb.InlineButton
+	if row := navRow(page, totalPages, b.btnSearchFirst, b.btnSearchPrev, b.btnSearchNext, b.btnSearchLast); len(row) > 0 {
+		btns = append(btns, row)
+	}
+	opts := &tb.SendOptions{ParseMode: tb.ModeHTML, ReplyMarkup: &tb.ReplyMarkup{InlineKeyboard: btns}, DisableWebPagePreview: true}
+
+	if c != nil {
+		_ = c.Edit(text, opts)
+	} else {
+		_, _ = b.bot.Send(tb.ChatID(chatID), text, opts)
+	}
+}
+
+// sourceHost — короткое имя источника для списка
+func sourceHost(source string) string {
+	if u, err := url.Parse(source); err == nil && u.Host != "" {
+		return strings.TrimPrefix(u.Host, "www.")
+	}
+	return source
+}
@@ -0,0 +1,51 @@
+package bot
+
+import (
+	"errors"
+	"log"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+	tb "gopkg.in/telebot.v3"
+)
+
+// send отправляет сообщение и разбирает ошибки Telegram: заблокировавших бота
+// и удалённых пользователей помечает неактивными, а после перевода группы
+// в супергруппу переносит данные на новый chat ID и повторяет отправку
+func (b *Bot) send(chatID int64, what interface{}, opts ...interface{}) (*tb.Message, error) {
+	msg, err := b.bot.Send(tb.ChatID(chatID), what, opts...)
+	if err == nil {
+		return msg, nil
+	}
+
+	if status := inactiveStatus(err); status != "" {
+		log.Printf("Пользователь %d недоступен (%s), рассылки остановлены", chatID, status)
+		if err := b.store.Users.DeactivateUser(chatID, status); err != nil {
+			log.Printf("Ошибка смены статуса пользователя %d: %v", chatID, err)
+		}
+		return nil, err
+	}
+
+	var migrated tb.GroupError
+	if errors.As(err, &migrated) && migrated.MigratedTo != 0 {
+		log.Printf("Чат %d переехал в %d", chatID, migrated.MigratedTo)
+		if err := b.store.Users.MigrateUserChat(chatID, migrated.MigratedTo); err != nil {
+			log.Printf("Ошибка переноса чата %d: %v", chatID, err)
+		}
+		return b.bot.Send(tb.ChatID(migrated.MigratedTo), what, opts...)
+	}
+	return nil, err
+}
+
+// inactiveStatus — статус пользователя, которому больше нельзя писать,
+// или "", если ошибка временная
+func inactiveStatus(err error) string {
+	switch {
+	case errors.Is(err, tb.ErrBlockedByUser),
+		errors.Is(err, tb.ErrKickedFromGroup),
+		errors.Is(err, tb.ErrKickedFromSuperGroup):
+		return storage.UserBlocked
+	case errors.Is(err, tb.ErrUserIsDeactivated):
+		return storage.UserDeactivated
+	}
+	return ""
+}
@@ -0,0 +1,209 @@
+package bot
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/settings"
+	tb "gopkg.in/telebot.v3"
+)
+
+// Сколько изменений показывает /settinghistory
+const settingHistoryLimit = 20
+
+// Режим ввода значения настройки после кнопки «изменить»: pendingSetting + ключ
+const pendingSetting = "set:"
+
+const setUsage = "⚠️ Формат: /set <ключ> <значение>, список настроек: /set"
+
+// settingValues возвращает настройки со значениями по умолчанию
+func (b *Bot) settingValues() settings.Values {
+	stored, err := b.store.Settings.GetAllSettings()
+	if err != nil {
+		log.Printf("Ошибка чтения настроек: %v", err)
+	}
+	return settings.Resolve(stored)
+}
+
+// setSetting проверяет значение по реестру и сохраняет его с записью в историю
+// и в журнал действий под именем action. Возвращает значение в каноническом виде
+func (b *Bot) setSetting(adminID int64, action, key, value string) (parsed string, err error) {
+	defer func() { b.audit(adminID, action, key+"="+value, resultOf(err)) }()
+	s, ok := settings.Lookup(key)
+	if !ok {
+		return "", fmt.Errorf("неизвестная настройка %s", key)
+	}
+	parsed, err = s.Parse(value)
+	if err != nil {
+		return "", err
+	}
+	if err := b.store.Settings.SetSetting(key, parsed, adminID); err != nil {
+		log.Printf("Ошибка сохранения настройки %s: %v", key, err)
+		return "", fmt.Errorf("ошибка сохранения")
+	}
+	return parsed, nil
+}
+
+// HandleSet — /set [ключ значение] для админов
+func (b *Bot) HandleSet(userID int64, arg string) {
+	key, value, _ := strings.Cut(strings.TrimSpace(arg), " ")
+	if key == "" {
+		b.ShowSettings(userID, nil)
+		return
+	}
+	if strings.TrimSpace(value) == "" {
+		b.SendMessage(userID, setUsage)
+		return
+	}
+	parsed, err := b.setSetting(userID, "set", key, value)
+	if err != nil {
+		b.SendMessage(userID, "⚠️ "+err.Error())
+		return
+	}
+	b.SendMessage(userID, fmt.Sprintf("✅ %s = %s", key, parsed))
+}
+
+// ShowSettings показывает известные настройки с кнопками редактирования,
+// а также сохранённые ключи, которых нет в реестре
+func (b *Bot) ShowSettings(chatID int64, c tb.Context) {
+	stored, _ := b.store.Settings.GetAllSettings()
+	values := settings.Resolve(stored)
+
+	text := "⚙️ Настройки:\n"
+	var rows [][]tb.InlineButton
+	for _, s := range settings.All() {
+		value := values.String(s.Key)
+		if value == "" {
+			value = "—"
+		}
+		suffix := ""
+		if _, ok := stored[s.Key]; !ok {
+			suffix = " (по умолчанию)"
+		}
+		text += fmt.Sprintf("\n<b>%s</b> = %s%s\n<i>%s; %s</i>\n",
+			s.Key, html.EscapeString(value), suffix, s.Description, html.EscapeString(s.TypeName()))
+
+		btn := b.btnSettingEdit
+		btn.Text = "✏️ " + s.Key
+		btn.Data = s.Key
+		rows = append(rows, []tb.InlineButton{btn})
+	}
+
+	var unknown []string
+	for k := range stored {
+		if _, ok := settings.Lookup(k); !ok {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		text += "\nНеизвестные ключи:\n"
+		for _, k := range unknown {
+			text += fmt.Sprintf("%s = %s\n", html.EscapeString(k), html.EscapeString(stored[k]))
+		}
+	}
+	text += "\nИзменить: /set <ключ> <значение>, история: /settinghistory <ключ>"
+
+	opts := &tb.SendOptions{ParseMode: tb.ModeHTML, ReplyMarkup: &tb.ReplyMarkup{InlineKeyboard: rows}, DisableWebPagePreview: true}
+	if c != nil {
+		_ = c.Edit(text, opts)
+	} else {
+		_, _ = b.bot.Send(tb.ChatID(chatID), text, opts)
+	}
+}
+
+// editSetting — кнопка «изменить»: для перечислений предлагает варианты,
+// для остальных ждёт значение следующим сообщением
+func (b *Bot) editSetting(c tb.Context) error {
+	userID := c.Sender().ID
+	if !b.IsAdmin(userID) {
+		return c.Respond()
+	}
+	s, ok := settings.Lookup(c.Data())
+	if !ok {
+		return c.Respond(&tb.CallbackResponse{Text: "Неизвестная настройка"})
+	}
+	current := b.settingValues().String(s.Key)
+
+	if s.Kind == settings.KindEnum || s.Kind == settings.KindBool {
+		options := s.Options
+		if s.Kind == settings.KindBool {
+			options = []string{"true", "false"}
+		}
+		var row []tb.InlineButton
+		for _, o := range options {
+			btn := b.btnSettingValue
+			btn.Text = o
+			if o == current {
+				btn.Text = "✅ " + o
+			}
+			btn.Data = s.Key + "=" + o
+			row = append(row, btn)
+		}
+		markup := &tb.ReplyMarkup{InlineKeyboard: [][]tb.InlineButton{row, {b.btnSettingList}}}
+		_ = c.Edit(fmt.Sprintf("⚙️ %s: %s\nСейчас: %s", s.Key, s.Description, current), markup)
+		return c.Respond()
+	}
+
+	b.pending[userID] = pendingSetting + s.Key
+	b.SendMessage(userID, fmt.Sprintf("Введите новое значение %s (%s): %s\nСейчас: %s",
+		s.Key, s.TypeName(), s.Description, current))
+	return c.Respond()
+}
+
+// setSettingValue — выбор варианта перечисления, Data — ключ=значение
+func (b *Bot) setSettingValue(c tb.Context) error {
+	userID := c.Sender().ID
+	if !b.IsAdmin(userID) {
+		return c.Respond()
+	}
+	key, value, _ := strings.Cut(c.Data(), "=")
+	parsed, err := b.setSetting(userID, "set", key, value)
+	if err != nil {
+		return c.Respond(&tb.CallbackResponse{Text: err.Error()})
+	}
+	b.ShowSettings(userID, c)
+	return c.Respond(&tb.CallbackResponse{Text: key + " = " + parsed})
+}
+
+// HandlePendingSetting принимает значение, введённое после кнопки «изменить»
+func (b *Bot) HandlePendingSetting(userID int64, key, value string) {
+	parsed, err := b.setSetting(userID, "set", key, value)
+	if err != nil {
+		b.SendMessage(userID, "⚠️ "+err.Error())
+		return
+	}
+	b.SendMessage(userID, fmt.Sprintf("✅ %s = %s", key, parsed))
+}
+
+// HandleSettingHistory — /settinghistory <ключ>
+func (b *Bot) HandleSettingHistory(userID int64, key string) {
+	if key == "" {
+		b.SendMessage(userID, "⚠️ Формат: /settinghistory <ключ>")
+		return
+	}
+	history, err := b.store.Settings.GetSettingHistory(key, settingHistoryLimit)
+	if err != nil {
+		log.Printf("Ошибка чтения истории настроек: %v", err)
+		b.SendMessage(userID, "❌ Ошибка чтения истории")
+		return
+	}
+	if len(history) == 0 {
+		b.SendMessage(userID, "История "+key+" пуста")
+		return
+	}
+
+	msg := "🕓 История " + key + ":\n"
+	for _, h := range history {
+		old := h.OldValue
+		if old == "" {
+			old = "—"
+		}
+		msg += fmt.Sprintf("\n%s, админ %d: %s → %s",
+			h.ChangedAt.In(moscow).Format("02.01.2006 15:04"), h.AdminID, old, h.NewValue)
+	}
+	b.SendMessage(userID, msg)
+}
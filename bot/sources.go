@@ -1,12 +1,332 @@
 package bot
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
 	tb "gopkg.in/telebot.v3"
 )
 
-func (b *Bot) ShowSourcesMenu(chatID int64) {
-	_, _ = b.bot.Send(
-		tb.ChatID(chatID),
-		"🔔 Здесь будет меню управления подписками.",
-	)
+const editSourceUsage = "⚠️ Формат: /editsource <номер|url> <поле> <значение>\n" +
+	"Поля: title, description, category, language, sort\n" +
+	"Номер — из /listsources, «-» вместо значения очищает поле\n" +
+	"Пример: /editsource 3 category Крипта"
+
+// Код языка источника: ru, en, ...
+var sourceLanguage = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// sourceTitle — название источника для списков; без названия — домен
+func sourceTitle(s storage.Source) string {
+	if s.Title != "" {
+		return s.Title
+	}
+	return sourceHost(s.URL)
+}
+
+// sourceNames — подписи источников к новостям: url → название
+func (b *Bot) sourceNames() map[string]string {
+	sources, err := b.store.Sources.GetSources()
+	if err != nil {
+		log.Printf("Ошибка загрузки источников: %v", err)
+	}
+	names := make(map[string]string, len(sources))
+	for _, s := range sources {
+		names[s.URL] = sourceTitle(s)
+	}
+	return names
+}
+
+// sourceLabel — подпись источника новости; удалённые источники — по домену
+func sourceLabel(names map[string]string, url string) string {
+	if name := names[url]; name != "" {
+		return name
+	}
+	return sourceHost(url)
+}
+
+// ShowSourcesMenu — /mysources: каталог источников по категориям и кнопки
+// подписки. Кнопка переключает подписку на источник
+func (b *Bot) ShowSourcesMenu(chatID int64, c tb.Context) {
+	sources, err := b.store.Sources.GetSources()
+	if err != nil {
+		log.Printf("Ошибка загрузки источников: %v", err)
+		b.SendMessage(chatID, b.text(chatID, msgSourcesError))
+		return
+	}
+	if len(sources) == 0 {
+		b.SendMessage(chatID, b.text(chatID, msgSourcesEmpty))
+		return
+	}
+	subs, _ := b.store.Subscriptions.GetUserSubscriptions(chatID)
+	subscribed := make(map[string]bool, len(subs))
+	for _, url := range subs {
+		subscribed[url] = true
+	}
+
+	// категории по алфавиту, без категории — в конце; внутри — порядок GetSources
+	byCategory := make(map[string][]storage.Source)
+	var categories []string
+	for _, s := range sources {
+		if _, ok := byCategory[s.Category]; !ok {
+			categories = append(categories, s.Category)
+		}
+		byCategory[s.Category] = append(byCategory[s.Category], s)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if (categories[i] == "") != (categories[j] == "") {
+			return categories[i] != ""
+		}
+		return categories[i] < categories[j]
+	})
+
+	// меню — одно сообщение: что не помещается в текст, остаётся только на кнопках
+	msg := b.text(chatID, msgSourcesMenu)
+	full := false
+	var rows [][]tb.InlineButton
+	for _, category := range categories {
+		name := category
+		if name == "" {
+			name = b.text(chatID, msgSourcesOther)
+		}
+		header := "\n\n📂 <b>" + html.EscapeString(name) + "</b>"
+		for i, s := range byCategory[category] {
+			mark := "▫️"
+			if subscribed[s.URL] {
+				mark = "✅"
+			}
+			btn := b.btnSourceToggle
+			btn.Text = mark + " " + sourceTitle(s)
+			btn.Data = sourceKey(s.URL)
+			rows = append(rows, []tb.InlineButton{btn})
+
+			entry := "\n" + mark + " " + html.EscapeString(sourceTitle(s))
+			if i == 0 {
+				entry = header + entry
+			}
+			if s.Language != "" {
+				entry += " · " + s.Language
+			}
+			if s.Description != "" {
+				entry += "\n<i>" + html.EscapeString(truncate(s.Description, 100)) + "</i>"
+			}
+			if !full && len(msg)+len(entry) > maxMessageLen-len("\n…") {
+				msg += "\n…"
+				full = true
+			}
+			if !full {
+				msg += entry
+			}
+		}
+	}
+
+	opts := &tb.SendOptions{
+		ParseMode:             tb.ModeHTML,
+		ReplyMarkup:           &tb.ReplyMarkup{InlineKeyboard: rows},
+		DisableWebPagePreview: true,
+	}
+	if c != nil {
+		_ = c.Edit(msg, opts)
+	} else {
+		_, _ = b.send(chatID, msg, opts)
+	}
+}
+
+// toggleSource подписывает на источник или отписывает от него (кнопка /mysources)
+func (b *Bot) toggleSource(c tb.Context) error {
+	chatID := c.Sender().ID
+	sources, err := b.store.Sources.GetSources()
+	if err != nil {
+		log.Printf("Ошибка загрузки источников: %v", err)
+		return c.Respond(&tb.CallbackResponse{Text: b.text(chatID, msgSourcesError)})
+	}
+	var url string
+	for _, s := range sources {
+		if sourceKey(s.URL) == c.Data() {
+			url = s.URL
+			break
+		}
+	}
+	if url == "" {
+		// источник удалили, пока меню было открыто
+		b.ShowSourcesMenu(chatID, c)
+		return c.Respond()
+	}
+
+	key := msgSubscribed
+	removed, err := b.store.Subscriptions.Unsubscribe(chatID, url)
+	if err == nil && removed {
+		key = msgUnsubscribed
+	} else if err == nil {
+		_, err = b.store.Subscriptions.Subscribe(chatID, url)
+	}
+	if err != nil {
+		log.Printf("Ошибка изменения подписки %d на %s: %v", chatID, url, err)
+		return c.Respond(&tb.CallbackResponse{Text: b.text(chatID, msgSubscribeError)})
+	}
+	b.ShowSourcesMenu(chatID, c)
+	return c.Respond(&tb.CallbackResponse{Text: b.text(chatID, key)})
+}
+
+// sourceKey — короткий ключ источника для callback data: URL может не уместиться в 64 байта
+func sourceKey(url string) string {
+	sum := sha1.Sum([]byte(url))
+	return hex.EncodeToString(sum[:8])
+}
+
+// ListSources — /listsources для админов: источники со всеми метаданными
+func (b *Bot) ListSources(userID int64) {
+	sources, err := b.store.Sources.GetSources()
+	if err != nil {
+		log.Printf("Ошибка загрузки источников: %v", err)
+		b.SendMessage(userID, "❌ Ошибка загрузки источников")
+		return
+	}
+	if len(sources) == 0 {
+		b.SendMessage(userID, "⚠️ В базе нет источников")
+		return
+	}
+
+	out := "📑 Источники:\n"
+	for i, s := range sources {
+		entry := fmt.Sprintf("\n%d. %s", i+1, formatSource(s))
+		if len(out)+len(entry) > maxMessageLen {
+			b.SendMessage(userID, out)
+			out = ""
+		}
+		out += entry
+	}
+	b.SendMessage(userID, out+"\nИзменить: /editsource <номер> <поле> <значение>")
+}
+
+// formatSource — карточка источника для админа
+func formatSource(s storage.Source) string {
+	title := s.Title
+	if title == "" {
+		title = sourceHost(s.URL) + " (без названия)"
+	}
+	out := title + "\n"
+
+	var meta []string
+	if s.Category != "" {
+		meta = append(meta, "📂 "+s.Category)
+	}
+	if s.Language != "" {
+		meta = append(meta, "🌐 "+s.Language)
+	}
+	if s.SortOrder != 0 {
+		meta = append(meta, fmt.Sprintf("⇅ %d", s.SortOrder))
+	}
+	if s.Priority != 0 {
+		meta = append(meta, fmt.Sprintf("⚡️ %+d", s.Priority))
+	}
+	if len(meta) > 0 {
+		out += strings.Join(meta, " · ") + "\n"
+	}
+	if s.Description != "" {
+		out += truncate(s.Description, 150) + "\n"
+	}
+	out += s.URL + "\n"
+	if !s.AddedAt.IsZero() {
+		added := "➕ " + s.AddedAt.In(moscow).Format("02.01.2006")
+		if s.AddedBy != 0 {
+			added += fmt.Sprintf(", админ %d", s.AddedBy)
+		}
+		out += added + "\n"
+	}
+	return out
+}
+
+// HandleEditSource — /editsource для админов
+func (b *Bot) HandleEditSource(userID int64, arg string) {
+	ref, rest, _ := strings.Cut(arg, " ")
+	field, value, _ := strings.Cut(strings.TrimSpace(rest), " ")
+	value = strings.TrimSpace(value)
+	if ref == "" {
+		b.SendMessage(userID, editSourceUsage)
+		return
+	}
+
+	s, ok, err := b.findSource(ref)
+	if err != nil {
+		log.Printf("Ошибка загрузки источника: %v", err)
+		b.SendMessage(userID, "❌ Ошибка загрузки источника")
+		return
+	}
+	if !ok {
+		b.SendMessage(userID, "⚠️ Источник не найден. Номера — в /listsources")
+		return
+	}
+	if field == "" {
+		b.SendMessage(userID, formatSource(s)+"\n"+editSourceUsage)
+		return
+	}
+	if value == "" {
+		b.SendMessage(userID, editSourceUsage)
+		return
+	}
+	if value == "-" {
+		value = ""
+	}
+
+	switch field {
+	case "title":
+		s.Title = value
+	case "description":
+		s.Description = value
+	case "category":
+		s.Category = value
+	case "language":
+		value = strings.ToLower(value)
+		if value != "" && !sourceLanguage.MatchString(value) {
+			b.SendMessage(userID, "⚠️ Язык — код из 2–3 латинских букв: ru, en")
+			return
+		}
+		s.Language = value
+	case "sort":
+		if value == "" {
+			value = "0"
+		}
+		order, err := strconv.Atoi(value)
+		if err != nil {
+			b.SendMessage(userID, "⚠️ Порядок должен быть целым числом")
+			return
+		}
+		s.SortOrder = order
+	default:
+		b.SendMessage(userID, editSourceUsage)
+		return
+	}
+
+	args := s.URL + " " + field + " " + value
+	if ok, err := b.store.Sources.UpdateSource(s); err != nil {
+		b.audit(userID, "editsource", args, resultOf(err))
+		b.SendMessage(userID, "❌ Ошибка сохранения источника")
+	} else if !ok {
+		b.audit(userID, "editsource", args, "источник не найден")
+		b.SendMessage(userID, "⚠️ Источник не найден")
+	} else {
+		b.audit(userID, "editsource", args, resultOf(nil))
+		b.SendMessage(userID, "✅ Источник обновлён:\n"+formatSource(s))
+	}
+}
+
+// findSource ищет источник по номеру из /listsources или по URL
+func (b *Bot) findSource(ref string) (storage.Source, bool, error) {
+	n, err := strconv.Atoi(ref)
+	if err != nil {
+		return b.store.Sources.GetSource(ref)
+	}
+	sources, err := b.store.Sources.GetSources()
+	if err != nil || n < 1 || n > len(sources) {
+		return storage.Source{}, false, err
+	}
+	return sources[n-1], true, nil
 }
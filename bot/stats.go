@@ -0,0 +1,217 @@
+package bot
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/chart"
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+	tb "gopkg.in/telebot.v3"
+)
+
+// Графики /stats
+const (
+	statsNews   = "news"
+	statsPushes = "pushes"
+	statsUsers  = "users"
+)
+
+// Периоды /stats в днях; первый — по умолчанию
+var statsPeriods = []int{7, 30, 90}
+
+// Сколько источников показывать отдельно, остальные — одной серией
+const statsTopSources = 7
+
+var statsTitles = map[string]string{
+	statsNews:   "📰 Новости",
+	statsPushes: "📨 Push",
+	statsUsers:  "👥 Пользователи",
+}
+
+// HandleStats — /stats для админов
+func (b *Bot) HandleStats(userID int64) {
+	b.ShowStats(userID, nil, statsNews, statsPeriods[0])
+}
+
+// ShowStats отправляет или обновляет график с кнопками выбора графика и периода
+func (b *Bot) ShowStats(chatID int64, c tb.Context, kind string, days int) {
+	img, caption, err := b.renderStats(kind, days, time.Now())
+	if err != nil {
+		log.Printf("Ошибка построения графика %s: %v", kind, err)
+		if c != nil {
+			_ = c.Respond(&tb.CallbackResponse{Text: "❌ Ошибка построения графика"})
+		} else {
+			b.SendMessage(chatID, "❌ Ошибка построения графика")
+		}
+		return
+	}
+
+	var kinds, periods []tb.InlineButton
+	for _, k := range []string{statsNews, statsPushes, statsUsers} {
+		btn := b.btnStats
+		btn.Text = statsTitles[k]
+		if k == kind {
+			btn.Text = "✅ " + btn.Text
+		}
+		btn.Data = k + "|" + strconv.Itoa(days)
+		kinds = append(kinds, btn)
+	}
+	for _, d := range statsPeriods {
+		btn := b.btnStats
+		btn.Text = fmt.Sprintf("%dд", d)
+		if d == days {
+			btn.Text = "✅ " + btn.Text
+		}
+		btn.Data = kind + "|" + strconv.Itoa(d)
+		periods = append(periods, btn)
+	}
+	markup := &tb.ReplyMarkup{InlineKeyboard: [][]tb.InlineButton{kinds, periods}}
+
+	photo := &tb.Photo{File: tb.FromReader(bytes.NewReader(img)), Caption: caption}
+	if c != nil {
+		_ = c.Edit(photo, markup)
+	} else {
+		_, _ = b.bot.Send(tb.ChatID(chatID), photo, markup)
+	}
+}
+
+// statsButton переключает график или период (кнопка)
+func (b *Bot) statsButton(c tb.Context) error {
+	if !b.IsAdmin(c.Sender().ID) {
+		return c.Respond()
+	}
+	kind, period, _ := strings.Cut(c.Data(), "|")
+	days, err := strconv.Atoi(period)
+	if err != nil || statsTitles[kind] == "" {
+		return c.Respond()
+	}
+	b.ShowStats(c.Sender().ID, c, kind, days)
+	return c.Respond()
+}
+
+// renderStats строит PNG и подпись к нему за последние days дней (UTC)
+func (b *Bot) renderStats(kind string, days int, now time.Time) ([]byte, string, error) {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := today.AddDate(0, 0, -(days - 1))
+
+	switch kind {
+	case statsPushes:
+		counts, err := b.store.Stats.CountPushesByDay(from)
+		if err != nil {
+			return nil, "", err
+		}
+		labels, series, total := dailySeries(counts, from, days, nil)
+		img, err := chart.Bars(labels, series)
+		return img, fmt.Sprintf("📨 Отправлено push-уведомлений за %d дн.: %d", days, total), err
+
+	case statsUsers:
+		// неделя с понедельника; первая неделя — та, в которую попадает начало периода
+		weekday := (int(from.Weekday()) + 6) % 7
+		from = from.AddDate(0, 0, -weekday)
+		counts, err := b.store.Stats.CountNewUsersByDay(from)
+		if err != nil {
+			return nil, "", err
+		}
+		weeks := int(today.Sub(from).Hours()/24)/7 + 1
+		values := make([]float64, weeks)
+		labels := make([]string, weeks)
+		total := 0
+		for i := range labels {
+			labels[i] = from.AddDate(0, 0, 7*i).Format("02.01")
+		}
+		for _, c := range counts {
+			if w := int(c.Day.Sub(from).Hours()/24) / 7; w >= 0 && w < weeks {
+				values[w] += float64(c.Count)
+				total += c.Count
+			}
+		}
+		img, err := chart.Bars(labels, []chart.Series{{Name: "users", Values: values}})
+		return img, fmt.Sprintf("👥 Новые пользователи по неделям с %s: %d", from.Format("02.01.2006"), total), err
+
+	default:
+		counts, err := b.store.Stats.CountNewsByDay(from)
+		if err != nil {
+			return nil, "", err
+		}
+		labels, series, total := dailySeries(counts, from, days, sourceName)
+		img, err := chart.Bars(labels, series)
+		return img, fmt.Sprintf("📰 Новости по источникам за %d дн.: %d", days, total), err
+	}
+}
+
+// dailySeries раскладывает счётчики по дням периода. name переводит ключ в подпись
+// легенды; nil — одна серия без разреза. Ключи сверх statsTopSources
+// объединяются в серию «other»
+func dailySeries(counts []storage.DayCount, from time.Time, days int, name func(string) string) ([]string, []chart.Series, int) {
+	labels := make([]string, days)
+	for i := range labels {
+		labels[i] = from.AddDate(0, 0, i).Format("02.01")
+	}
+
+	totals := make(map[string]int)
+	total := 0
+	for _, c := range counts {
+		if name == nil {
+			c.Key = ""
+		}
+		totals[c.Key] += c.Count
+		total += c.Count
+	}
+	keys := make([]string, 0, len(totals))
+	for k := range totals {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if totals[keys[i]] != totals[keys[j]] {
+			return totals[keys[i]] > totals[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	index := make(map[string]int)
+	var series []chart.Series
+	for i, k := range keys {
+		if i == statsTopSources && len(keys) > statsTopSources+1 {
+			series = append(series, chart.Series{Name: "other", Values: make([]float64, days)})
+			for _, rest := range keys[i:] {
+				index[rest] = i
+			}
+			break
+		}
+		label := "total"
+		if name != nil {
+			label = name(k)
+		}
+		index[k] = i
+		series = append(series, chart.Series{Name: label, Values: make([]float64, days)})
+	}
+	if len(series) == 0 {
+		series = []chart.Series{{Name: "total", Values: make([]float64, days)}}
+	}
+
+	for _, c := range counts {
+		if name == nil {
+			c.Key = ""
+		}
+		if d := int(c.Day.Sub(from).Hours() / 24); d >= 0 && d < days {
+			series[index[c.Key]].Values[d] += float64(c.Count)
+		}
+	}
+	return labels, series, total
+}
+
+// sourceName — короткое имя источника для легенды: домен без www
+func sourceName(src string) string {
+	u, err := url.Parse(src)
+	if err != nil || u.Hostname() == "" {
+		return src
+	}
+	return strings.TrimPrefix(u.Hostname(), "www.")
+}
@@ -0,0 +1,86 @@
+package bot
+
+import (
+	"fmt"
+	"html"
+	"log"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/sentiment"
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+	"github.com/FFFFFFFFFFj/trade-news-bot/tagger"
+	tb "gopkg.in/telebot.v3"
+)
+
+// Новостей в одном сообщении /unread
+const unreadPageSize = 5
+
+// markRead отмечает доставленные пользователю новости прочитанными
+func (b *Bot) markRead(userID int64, news []storage.NewsItem) {
+	if len(news) == 0 {
+		return
+	}
+	links := make([]string, len(news))
+	for i, n := range news {
+		links[i] = n.Link
+	}
+	if err := b.store.Reads.MarkNewsRead(userID, links); err != nil {
+		log.Printf("Ошибка отметки прочитанного: %v", err)
+	}
+}
+
+// ShowUnread показывает самые свежие непрочитанные новости и отмечает их прочитанными.
+// «Ещё» показывает следующую порцию: показанные уже не попадут в выборку
+func (b *Bot) ShowUnread(chatID int64, c tb.Context) {
+	total, _ := b.store.Reads.CountUnreadNewsForUser(chatID)
+	news, err := b.store.Reads.GetUnreadNewsForUser(chatID, unreadPageSize)
+	if err != nil {
+		log.Printf("Ошибка выборки непрочитанного: %v", err)
+	}
+	if len(news) == 0 {
+		if c != nil {
+			_ = c.Edit("✅ Непрочитанных новостей нет.")
+		} else {
+			b.SendMessage(chatID, "✅ Непрочитанных новостей нет.")
+		}
+		return
+	}
+
+	text := fmt.Sprintf("📬 Непрочитанные новости: %d\n\n", total)
+	names := b.sourceNames()
+	for _, n := range news {
+		text += fmt.Sprintf("• %s <b>%s</b>\n", sentiment.Emoji(n.Sentiment), html.EscapeString(n.Title))
+		text += "🗞 " + html.EscapeString(sourceLabel(names, n.Source)) + "\n"
+		if len(n.Tags) > 0 {
+			text += tagger.Hashtags(n.Tags) + "\n"
+		}
+		text += n.Link + "\n\n"
+	}
+	b.markRead(chatID, news)
+
+	var markup *tb.ReplyMarkup
+	if left := total - len(news); left > 0 {
+		text += fmt.Sprintf("Осталось: %d", left)
+		markup = &tb.ReplyMarkup{InlineKeyboard: [][]tb.InlineButton{
+			{b.btnUnreadMore},
+			{b.btnMarkAllRead},
+		}}
+	}
+
+	opts := &tb.SendOptions{ParseMode: tb.ModeHTML, ReplyMarkup: markup, DisableWebPagePreview: true}
+	if c != nil {
+		_ = c.Edit(text, opts)
+	} else {
+		_, _ = b.bot.Send(tb.ChatID(chatID), text, opts)
+	}
+}
+
+// MarkAllRead отмечает прочитанными все новости из подписок (кнопка в /start и /unread)
+func (b *Bot) MarkAllRead(c tb.Context) error {
+	marked, err := b.store.Reads.MarkAllNewsRead(c.Sender().ID)
+	if err != nil {
+		log.Printf("Ошибка отметки прочитанного: %v", err)
+		return c.Respond(&tb.CallbackResponse{Text: "❌ Ошибка"})
+	}
+	_ = c.Edit(fmt.Sprintf("✅ Отмечено прочитанными: %d", marked))
+	return c.Respond(&tb.CallbackResponse{Text: "Все новости прочитаны"})
+}
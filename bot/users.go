@@ -0,0 +1,142 @@
+package bot
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+	tb "gopkg.in/telebot.v3"
+)
+
+// сколько профилей показывать в /user по имени
+const userLookupLimit = 10
+
+// trackUser — middleware: обновляет профиль отправителя на каждом апдейте,
+// включая нажатия кнопок. Группа, в которой пишут, тоже заводится в users:
+// подписки и настройки группы хранятся по её chat ID
+func (b *Bot) trackUser(next tb.HandlerFunc) tb.HandlerFunc {
+	return func(c tb.Context) error {
+		u := c.Sender()
+		if chat := c.Chat(); chat != nil && (u == nil || chat.ID != u.ID) {
+			if err := b.store.Users.EnsureUser(chat.ID); err != nil {
+				log.Printf("Ошибка добавления чата %d: %v", chat.ID, err)
+			}
+		}
+		if u != nil && !u.IsBot {
+			err := b.store.Users.TouchUser(storage.UserProfile{
+				ID:           u.ID,
+				Username:     u.Username,
+				FirstName:    u.FirstName,
+				LastName:     u.LastName,
+				LanguageCode: u.LanguageCode,
+			})
+			if err != nil {
+				log.Printf("Ошибка обновления профиля %d: %v", u.ID, err)
+			}
+		}
+		return next(c)
+	}
+}
+
+// userStats — строки статистики пользователей для админа
+func (b *Bot) userStats() string {
+	now := time.Now()
+	seenDay, _ := b.store.Users.CountUsersSeenSince(now.Add(-24 * time.Hour))
+	seenWeek, _ := b.store.Users.CountUsersSeenSince(now.AddDate(0, 0, -7))
+	joinedWeek, _ := b.store.Users.CountUsersJoinedSince(now.AddDate(0, 0, -7))
+	text := fmt.Sprintf("Заходили за сутки: %d, за неделю: %d\nНовых за неделю: %d", seenDay, seenWeek, joinedWeek)
+
+	langs, _ := b.store.Users.GetUserLanguages()
+	if len(langs) > 0 {
+		codes := make([]string, 0, len(langs))
+		for code := range langs {
+			codes = append(codes, code)
+		}
+		sort.Slice(codes, func(i, j int) bool {
+			if langs[codes[i]] != langs[codes[j]] {
+				return langs[codes[i]] > langs[codes[j]]
+			}
+			return codes[i] < codes[j]
+		})
+		var parts []string
+		for _, code := range codes {
+			name := code
+			if name == "" {
+				name = "?"
+			}
+			parts = append(parts, fmt.Sprintf("%s %d", name, langs[code]))
+		}
+		text += "\nЯзыки: " + strings.Join(parts, ", ")
+	}
+	return text
+}
+
+// formatProfile описывает пользователя для админа
+func (b *Bot) formatProfile(p storage.UserProfile) string {
+	text := fmt.Sprintf("<b>%d</b>", p.ID)
+	if p.Username != "" {
+		text += " @" + html.EscapeString(p.Username)
+	}
+	if name := p.Name(); name != "" {
+		text += " " + html.EscapeString(name)
+	}
+	lang := p.LanguageCode
+	if lang == "" {
+		lang = "?"
+	}
+	text += fmt.Sprintf("\nЯзык: %s, статус: %s", html.EscapeString(lang), p.Status)
+	// дата прихода неизвестна у тех, кто был до профилей
+	var dates []string
+	if !p.JoinedAt.IsZero() {
+		dates = append(dates, "С нами с "+p.JoinedAt.In(moscow).Format("02.01.2006"))
+	}
+	if !p.LastSeenAt.IsZero() {
+		dates = append(dates, "Последний визит "+p.LastSeenAt.In(moscow).Format("02.01.2006 15:04"))
+	}
+	if len(dates) > 0 {
+		text += "\n" + strings.Join(dates, ", ")
+	}
+	return text
+}
+
+// HandleUserLookup — /user <id|@username|имя> для админов
+func (b *Bot) HandleUserLookup(userID int64, arg string) {
+	if arg == "" {
+		b.SendMessage(userID, "⚠️ Формат: /user <id|@username|имя>")
+		return
+	}
+
+	var found []storage.UserProfile
+	if id, err := strconv.ParseInt(arg, 10, 64); err == nil {
+		if p, ok, _ := b.store.Users.GetUser(id); ok {
+			found = append(found, p)
+		}
+	} else {
+		var err error
+		found, err = b.store.Users.FindUsers(arg, userLookupLimit)
+		if err != nil {
+			log.Printf("Ошибка поиска пользователей: %v", err)
+		}
+	}
+	if len(found) == 0 {
+		b.SendMessage(userID, "⚠️ Пользователь не найден")
+		return
+	}
+
+	var blocks []string
+	for _, p := range found {
+		block := b.formatProfile(p)
+		if len(found) == 1 {
+			subs, _ := b.store.Subscriptions.GetUserSubscriptionCount(p.ID)
+			schedules, _ := b.store.Autopost.GetUserAutopost(p.ID)
+			block += fmt.Sprintf("\nПодписок: %d\nДайджест: %s", subs, html.EscapeString(formatAutopost(schedules)))
+		}
+		blocks = append(blocks, block)
+	}
+	_, _ = b.bot.Send(tb.ChatID(userID), strings.Join(blocks, "\n\n"), &tb.SendOptions{ParseMode: tb.ModeHTML})
+}
@@ -0,0 +1,55 @@
+package bot
+
+import (
+	"fmt"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+	"github.com/FFFFFFFFFFj/trade-news-bot/tagger"
+)
+
+// resolveTicker превращает ввод пользователя (SBER, $AAPL, «Сбербанк») в тикер
+func (b *Bot) resolveTicker(input string) string {
+	if tags := storage.LoadTagger(b.store.Tags).Tag(input); len(tags) == 1 {
+		return tags[0]
+	}
+	return tagger.NormalizeTicker(input)
+}
+
+func (b *Bot) HandleWatch(userID int64, arg string) {
+	ticker := b.resolveTicker(arg)
+	if ticker == "" {
+		b.SendMessage(userID, "⚠️ Формат: /watch SBER")
+		return
+	}
+	if err := b.store.Watchlist.AddToWatchlist(userID, ticker); err != nil {
+		b.SendMessage(userID, "❌ Ошибка добавления в watchlist")
+		return
+	}
+	b.SendMessage(userID, fmt.Sprintf("👀 %s добавлен в watchlist. Новости по нему будут приходить из всех источников.", ticker))
+}
+
+func (b *Bot) HandleUnwatch(userID int64, arg string) {
+	ticker := b.resolveTicker(arg)
+	if ticker == "" {
+		b.SendMessage(userID, "⚠️ Формат: /unwatch SBER")
+		return
+	}
+	removed, err := b.store.Watchlist.RemoveFromWatchlist(userID, ticker)
+	switch {
+	case err != nil:
+		b.SendMessage(userID, "❌ Ошибка удаления из watchlist")
+	case !removed:
+		b.SendMessage(userID, fmt.Sprintf("⚠️ %s нет в вашем watchlist", ticker))
+	default:
+		b.SendMessage(userID, fmt.Sprintf("✅ %s удалён из watchlist", ticker))
+	}
+}
+
+func (b *Bot) ShowWatchlist(userID int64) {
+	tickers, _ := b.store.Watchlist.GetWatchlist(userID)
+	if len(tickers) == 0 {
+		b.SendMessage(userID, "Watchlist пуст. Добавьте тикер: /watch SBER")
+		return
+	}
+	b.SendMessage(userID, "👀 Ваш watchlist:\n"+tagger.Hashtags(tickers)+"\n\nУдалить: /unwatch <тикер>")
+}
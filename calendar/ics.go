@@ -0,0 +1,183 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultCategory — категория событий без CATEGORIES
+const DefaultCategory = "other"
+
+// Event — событие экономического календаря
+type Event struct {
+	UID         string
+	Title       string
+	Description string
+	Category    string
+	// Start — момент начала; у событий на весь день — их дата (полночь UTC),
+	// а не момент: см. StartIn
+	Start  time.Time
+	AllDay bool
+}
+
+// StartIn — начало события в поясе loc. Событие на весь день начинается
+// в полночь своей даты по loc, где бы ни был пользователь
+func (e Event) StartIn(loc *time.Location) time.Time {
+	if e.AllDay {
+		y, m, d := e.Start.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, loc)
+	}
+	return e.Start.In(loc)
+}
+
+// Fetch скачивает и разбирает ICS по URL
+func Fetch(url string) ([]Event, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ics fetch %s: %s", url, resp.Status)
+	}
+	return Parse(resp.Body)
+}
+
+// Parse разбирает VEVENT-записи из ICS (RFC 5545)
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	var cur *Event
+	tz := make(zones)
+	for _, line := range lines {
+		name, params, value := splitProperty(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			cur = &Event{}
+		case name == "END" && value == "VEVENT":
+			if cur != nil && !cur.Start.IsZero() {
+				if cur.Category == "" {
+					cur.Category = DefaultCategory
+				}
+				if cur.UID == "" {
+					cur.UID = fmt.Sprintf("%s@%d", cur.Title, cur.Start.Unix())
+				}
+				events = append(events, *cur)
+			}
+			cur = nil
+		case cur == nil:
+			continue
+		case name == "UID":
+			cur.UID = value
+		case name == "SUMMARY":
+			cur.Title = unescape(value)
+		case name == "DESCRIPTION":
+			cur.Description = unescape(value)
+		case name == "CATEGORIES":
+			// берём первую категорию из списка
+			cat := strings.SplitN(value, ",", 2)[0]
+			cur.Category = NormalizeCategory(unescape(cat))
+		case name == "DTSTART":
+			start, allDay, err := parseTime(value, params, tz)
+			if err != nil {
+				return nil, fmt.Errorf("DTSTART %q: %w", value, err)
+			}
+			cur.Start, cur.AllDay = start, allDay
+		}
+	}
+	return events, nil
+}
+
+// NormalizeCategory приводит категорию к виду для подписок
+func NormalizeCategory(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// unfold склеивает перенесённые строки (продолжение начинается с пробела или табуляции)
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, sc.Err()
+}
+
+// splitProperty разбирает «NAME;PARAM=VAL:value»
+func splitProperty(line string) (string, map[string]string, string) {
+	i := strings.Index(line, ":")
+	if i < 0 {
+		return strings.ToUpper(line), nil, ""
+	}
+	head, value := line[:i], line[i+1:]
+	parts := strings.Split(head, ";")
+	params := make(map[string]string)
+	for _, p := range parts[1:] {
+		if kv := strings.SplitN(p, "=", 2); len(kv) == 2 {
+			params[strings.ToUpper(kv[0])] = strings.Trim(kv[1], `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+// zones — пояса по TZID; неизвестные хранятся как nil, чтобы предупредить о них один раз
+type zones map[string]*time.Location
+
+// get возвращает пояс TZID; неизвестный пояс заменяется на UTC с записью в лог
+func (z zones) get(tzid string) *time.Location {
+	loc, ok := z[tzid]
+	if !ok {
+		var err error
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			log.Printf("ICS: неизвестный TZID %q, время событий считается в UTC: %v", tzid, err)
+			loc = nil
+		}
+		z[tzid] = loc
+	}
+	if loc == nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// parseTime разбирает DTSTART. Дата без времени (VALUE=DATE) — событие на весь день:
+// дата хранится полночью UTC и переводится в пояс пользователя через Event.StartIn
+func parseTime(value string, params map[string]string, tz zones) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == 8 {
+		t, err := time.Parse("20060102", value)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+
+	loc := time.UTC
+	if tzid := params["TZID"]; tzid != "" {
+		loc = tz.get(tzid)
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+func unescape(s string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
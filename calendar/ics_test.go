@@ -0,0 +1,80 @@
+package calendar
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	f, err := os.Open("testdata/events.ics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	events, err := Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 4 {
+		t.Fatalf("разобрано %d событий, ожидалось 4 (без DTSTART — пропускается): %+v", len(events), events)
+	}
+	byUID := make(map[string]Event)
+	for _, e := range events {
+		byUID[e.UID] = e
+	}
+
+	// TZID и перенесённые строки
+	rate := byUID["cbr-rate@example"]
+	if want := time.Date(2024, 7, 26, 10, 30, 0, 0, time.UTC); !rate.Start.Equal(want) || rate.AllDay {
+		t.Errorf("событие с TZID: %v (весь день %v), ожидалось %v", rate.Start, rate.AllDay, want)
+	}
+	if rate.Title != "Решение ЦБ РФ по ключевой ставке" {
+		t.Errorf("перенесённый SUMMARY: %q", rate.Title)
+	}
+	if rate.Description != "Пресс-релиз, затем пресс-конференция\nв 15:00" {
+		t.Errorf("DESCRIPTION: %q", rate.Description)
+	}
+	if rate.Category != "rates" {
+		t.Errorf("категория: %q", rate.Category)
+	}
+
+	// событие на весь день — дата, в любом поясе она та же
+	holiday := byUID["holiday@example"]
+	if !holiday.AllDay || holiday.Category != DefaultCategory {
+		t.Errorf("событие на весь день: %+v", holiday)
+	}
+	for _, name := range []string{"America/New_York", "Europe/Moscow", "Pacific/Auckland"} {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		start := holiday.StartIn(loc)
+		if y, m, d := start.Date(); y != 2024 || m != time.June || d != 12 || start.Hour() != 0 {
+			t.Errorf("начало события на весь день в %s: %v", name, start)
+		}
+	}
+
+	// UTC и UID по умолчанию
+	cpi := byUID["US CPI@1720701000"]
+	if cpi.Title != "US CPI" || !cpi.Start.Equal(time.Date(2024, 7, 11, 12, 30, 0, 0, time.UTC)) {
+		t.Errorf("событие в UTC без UID: %+v", events)
+	}
+
+	// неизвестный пояс: время в UTC и предупреждение в логе
+	unknown := byUID["unknown-tz@example"]
+	if !unknown.Start.Equal(time.Date(2024, 8, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("событие с неизвестным TZID: %v", unknown.Start)
+	}
+	if !strings.Contains(logged.String(), "Russian Standard Time") {
+		t.Errorf("неизвестный TZID не попал в лог: %q", logged.String())
+	}
+}
@@ -0,0 +1,227 @@
+// Package chart рисует простые столбчатые диаграммы в PNG без внешних сервисов.
+// Подписи выводятся встроенным моноширинным шрифтом, поэтому только ASCII:
+// заголовки на русском передаются подписью к картинке
+package chart
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strconv"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Размер картинки и отступы области графика
+const (
+	width        = 900
+	height       = 480
+	marginLeft   = 56
+	marginRight  = 20
+	marginTop    = 20
+	marginBottom = 36
+	// строка легенды
+	legendRow = 18
+	yTicks    = 5
+)
+
+var (
+	background = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	axisColor  = color.RGBA{0x44, 0x44, 0x44, 0xff}
+	gridColor  = color.RGBA{0xe6, 0xe6, 0xe6, 0xff}
+	textColor  = color.RGBA{0x22, 0x22, 0x22, 0xff}
+
+	// цвета серий по порядку
+	palette = []color.RGBA{
+		{0x42, 0x85, 0xf4, 0xff},
+		{0xea, 0x43, 0x35, 0xff},
+		{0xfb, 0xbc, 0x05, 0xff},
+		{0x34, 0xa8, 0x53, 0xff},
+		{0xab, 0x47, 0xbc, 0xff},
+		{0x00, 0xac, 0xc1, 0xff},
+		{0xff, 0x70, 0x43, 0xff},
+		{0x9e, 0x9e, 0x9e, 0xff},
+	}
+
+	face = basicfont.Face7x13
+)
+
+// Series — ряд значений, по одному на каждую метку оси X
+type Series struct {
+	Name   string
+	Values []float64
+}
+
+// Bars рисует столбчатую диаграмму: серии складываются в один столбец
+// на каждую метку. Легенда выводится, если серий больше одной
+func Bars(labels []string, series []Series) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{background}, image.Point{}, draw.Src)
+
+	legendRows := 0
+	if len(series) > 1 {
+		legendRows = legendLayout(series, nil)
+	}
+	plot := image.Rect(marginLeft, marginTop, width-marginRight, height-marginBottom-legendRows*legendRow)
+
+	totals := make([]float64, len(labels))
+	for _, s := range series {
+		for i := range labels {
+			if i < len(s.Values) {
+				totals[i] += s.Values[i]
+			}
+		}
+	}
+	max := 0.0
+	for _, t := range totals {
+		max = math.Max(max, t)
+	}
+	top, step := niceScale(max)
+
+	// сетка и подписи оси Y
+	for i := 0; step*float64(i) <= top; i++ {
+		v := step * float64(i)
+		y := plot.Max.Y - int(float64(plot.Dy())*v/top)
+		hline(img, plot.Min.X, plot.Max.X, y, gridColor)
+		label := formatValue(v)
+		text(img, plot.Min.X-6-textWidth(label), y+4, label, textColor)
+	}
+
+	// столбцы
+	if n := len(labels); n > 0 {
+		slot := float64(plot.Dx()) / float64(n)
+		barWidth := int(slot * 0.7)
+		if barWidth < 1 {
+			barWidth = 1
+		}
+		for i := range labels {
+			x0 := plot.Min.X + int(slot*float64(i)+(slot-float64(barWidth))/2)
+			base := 0.0
+			for si, s := range series {
+				if i >= len(s.Values) || s.Values[i] <= 0 {
+					continue
+				}
+				y0 := plot.Max.Y - int(float64(plot.Dy())*(base+s.Values[i])/top)
+				y1 := plot.Max.Y - int(float64(plot.Dy())*base/top)
+				fill(img, image.Rect(x0, y0, x0+barWidth, y1), palette[si%len(palette)])
+				base += s.Values[i]
+			}
+		}
+
+		// подписи оси X: не чаще, чем помещаются
+		labelWidth := 0
+		for _, l := range labels {
+			if w := textWidth(l); w > labelWidth {
+				labelWidth = w
+			}
+		}
+		every := int(math.Ceil(float64(labelWidth+8) / slot))
+		if every < 1 {
+			every = 1
+		}
+		for i := 0; i < n; i += every {
+			cx := plot.Min.X + int(slot*float64(i)+slot/2)
+			text(img, cx-textWidth(labels[i])/2, plot.Max.Y+16, labels[i], textColor)
+		}
+	}
+
+	// оси
+	hline(img, plot.Min.X, plot.Max.X, plot.Max.Y, axisColor)
+	vline(img, plot.Min.X, plot.Min.Y, plot.Max.Y, axisColor)
+
+	if legendRows > 0 {
+		legendLayout(series, func(si, x, row int) {
+			y := plot.Max.Y + marginBottom + row*legendRow
+			fill(img, image.Rect(x, y-9, x+10, y+1), palette[si%len(palette)])
+			text(img, x+14, y, series[si].Name, textColor)
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// legendLayout раскладывает элементы легенды по строкам и возвращает их число;
+// place, если задан, вызывается для каждого элемента
+func legendLayout(series []Series, place func(si, x, row int)) int {
+	x, row := marginLeft, 0
+	for si, s := range series {
+		w := 14 + textWidth(s.Name) + 16
+		if x+w > width-marginRight && x > marginLeft {
+			x, row = marginLeft, row+1
+		}
+		if place != nil {
+			place(si, x, row)
+		}
+		x += w
+	}
+	return row + 1
+}
+
+// niceScale подбирает шаг сетки кратный 1, 2, 2.5 или 5 и верх оси Y
+// не выше yTicks шагов
+func niceScale(max float64) (top, step float64) {
+	if max <= 0 {
+		return yTicks, 1
+	}
+	raw := max / yTicks
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, m := range []float64{1, 2, 2.5, 5, 10} {
+		// дробные шаги для счётчиков не нужны
+		if m*mag != math.Trunc(m*mag) {
+			continue
+		}
+		step = m * mag
+		if step >= raw {
+			break
+		}
+	}
+	if step < 1 {
+		step = 1
+	}
+	return math.Ceil(max/step) * step, step
+}
+
+func formatValue(v float64) string {
+	switch {
+	case v >= 1e6:
+		return strconv.FormatFloat(v/1e6, 'f', -1, 64) + "M"
+	case v >= 1e4:
+		return strconv.FormatFloat(v/1e3, 'f', -1, 64) + "k"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func text(img *image.RGBA, x, y int, s string, c color.Color) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(s)
+}
+
+func textWidth(s string) int {
+	return font.MeasureString(face, s).Ceil()
+}
+
+func fill(img *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, &image.Uniform{c}, image.Point{}, draw.Src)
+}
+
+func hline(img *image.RGBA, x0, x1, y int, c color.Color) {
+	fill(img, image.Rect(x0, y, x1+1, y+1), c)
+}
+
+func vline(img *image.RGBA, x, y0, y1 int, c color.Color) {
+	fill(img, image.Rect(x, y0, x+1, y1+1), c)
+}
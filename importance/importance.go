@@ -0,0 +1,54 @@
+package importance
+
+import "strings"
+
+const (
+	// CoverageWeight — вес каждого другого источника, написавшего о тех же тикерах
+	CoverageWeight = 2
+	// MaxCoverage ограничивает учитываемое число других источников
+	MaxCoverage = 5
+)
+
+// Rule — правило администратора: ключевое слово и его вес.
+// Ключевое слово ищется как подстрока, поэтому можно задавать основу: «ставк»
+type Rule struct {
+	Keyword string
+	Weight  int
+}
+
+// Scorer считает важность новости
+type Scorer struct {
+	rules      []Rule
+	priorities map[string]int
+}
+
+// New создаёт оценщик по правилам и приоритетам источников (url → приоритет)
+func New(rules []Rule, priorities map[string]int) *Scorer {
+	normalized := make([]Rule, 0, len(rules))
+	for _, r := range rules {
+		if kw := NormalizeKeyword(r.Keyword); kw != "" {
+			normalized = append(normalized, Rule{Keyword: kw, Weight: r.Weight})
+		}
+	}
+	return &Scorer{rules: normalized, priorities: priorities}
+}
+
+// Score = сумма весов сработавших правил + приоритет источника + охват другими источниками
+func (s *Scorer) Score(source, text string, coverage int) int {
+	text = strings.ToLower(text)
+	score := s.priorities[source]
+	for _, r := range s.rules {
+		if strings.Contains(text, r.Keyword) {
+			score += r.Weight
+		}
+	}
+	if coverage > MaxCoverage {
+		coverage = MaxCoverage
+	}
+	return score + coverage*CoverageWeight
+}
+
+// NormalizeKeyword приводит ключевое слово к виду для сравнения
+func NormalizeKeyword(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
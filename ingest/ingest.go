@@ -0,0 +1,153 @@
+package ingest
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/importance"
+	"github.com/FFFFFFFFFFj/trade-news-bot/sentiment"
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+	"github.com/FFFFFFFFFFj/trade-news-bot/summary"
+	"github.com/FFFFFFFFFFj/trade-news-bot/tagger"
+	"github.com/mmcdole/gofeed"
+)
+
+// Количество предложений в саммари новости
+const summarySentences = 3
+
+// FetchAndStore загружает новости из всех источников и сохраняет новые.
+// Доставку получателям SaveNews ставит в outbox, отправляет её бот.
+// Возвращает число новых новостей
+func FetchAndStore(store *storage.Store) (int, error) {
+	allSources, err := store.Sources.GetSources()
+	if err != nil {
+		return 0, err
+	}
+
+	fp := gofeed.NewParser()
+	tg := storage.LoadTagger(store.Tags)
+	scorer := storage.LoadImportanceScorer(store.Importance, store.Sources)
+	added := 0
+
+	for _, source := range allSources {
+		src := source.URL
+		feed, err := fp.ParseURL(src)
+		if err != nil {
+			log.Printf("Ошибка парсинга %s: %v", src, err)
+			continue
+		}
+		fillSourceMeta(store, source, feed)
+
+		for _, item := range newItems(store, src, feed) {
+			inserted, err := saveItem(store, tg, scorer, item, src)
+			if err != nil {
+				log.Printf("Ошибка вставки новости: %v", err)
+				continue
+			}
+			if inserted {
+				added++
+			}
+		}
+	}
+
+	return added, nil
+}
+
+// FetchAndStoreForUser загружает новости только по подпискам конкретного пользователя
+func FetchAndStoreForUser(store *storage.Store, userID int64) error {
+	sources, err := store.Subscriptions.GetUserSubscriptions(userID)
+	if err != nil {
+		return err
+	}
+
+	fp := gofeed.NewParser()
+	tg := storage.LoadTagger(store.Tags)
+	scorer := storage.LoadImportanceScorer(store.Importance, store.Sources)
+	for _, src := range sources {
+		feed, err := fp.ParseURL(src)
+		if err != nil {
+			continue
+		}
+		if source, ok, err := store.Sources.GetSource(src); err == nil && ok {
+			fillSourceMeta(store, source, feed)
+		}
+		for _, item := range newItems(store, src, feed) {
+			_, _ = saveItem(store, tg, scorer, item, src)
+		}
+	}
+
+	return nil
+}
+
+// fillSourceMeta дописывает источнику название, описание и язык из фида,
+// если админ их ещё не задал
+func fillSourceMeta(store *storage.Store, source storage.Source, feed *gofeed.Feed) {
+	if source.Title != "" && source.Description != "" && source.Language != "" {
+		return
+	}
+	title := strings.TrimSpace(feed.Title)
+	description := strings.TrimSpace(feed.Description)
+	// "en-US" → "en"
+	language, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(feed.Language)), "-")
+	if title == "" && description == "" && language == "" {
+		return
+	}
+	if err := store.Sources.FillSourceMeta(source.URL, title, description, language); err != nil {
+		log.Printf("Ошибка сохранения метаданных %s: %v", source.URL, err)
+	}
+}
+
+// newItems отбрасывает уже сохранённые новости фида, чтобы не считать для них
+// саммари, теги и важность. При ошибке проверки возвращает все: повторы
+// всё равно отсеет SaveNews
+func newItems(store *storage.Store, src string, feed *gofeed.Feed) []*gofeed.Item {
+	links := make([]string, len(feed.Items))
+	for i, item := range feed.Items {
+		links[i] = item.Link
+	}
+	known, err := store.News.KnownLinks(links)
+	if err != nil {
+		log.Printf("Ошибка проверки новостей %s: %v", src, err)
+		return feed.Items
+	}
+
+	var items []*gofeed.Item
+	for _, item := range feed.Items {
+		if !known[item.Link] {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// saveItem сохраняет новость из фида вместе с её саммари, тегами и оценками.
+// inserted == false, если такая новость уже была в базе
+func saveItem(store *storage.Store, tg *tagger.Tagger, scorer *importance.Scorer, item *gofeed.Item, src string) (inserted bool, err error) {
+	pub := item.PublishedParsed
+	if pub == nil {
+		now := time.Now()
+		pub = &now
+	}
+
+	// полный текст статьи (content:encoded) предпочтительнее короткого описания
+	text := item.Description
+	if len(item.Content) > len(text) {
+		text = item.Content
+	}
+
+	n := storage.NewsItem{
+		Title:     item.Title,
+		Link:      item.Link,
+		PubDate:   *pub,
+		Source:    src,
+		Summary:   summary.Summarize(text, summarySentences),
+		Sentiment: sentiment.Score(item.Title),
+	}
+	description := summary.Clean(text)
+	n.Tags = tg.Tag(n.Title, description)
+	coverage, _ := store.News.GetCoverage(src, n.Tags)
+	n.Importance = scorer.Score(src, n.Title+"\n"+description, coverage)
+
+	return store.News.SaveNews(n, description)
+}
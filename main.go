@@ -1,18 +1,43 @@
 package main
 //
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
 	"log"
 	"os"
+	"strconv"
 	//"time"
+	_ "time/tzdata"
 
 	"github.com/joho/godotenv"
 	"github.com/FFFFFFFFFFj/trade-news-bot/bot"
+	"github.com/FFFFFFFFFFj/trade-news-bot/quotes"
 	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage/postgres"
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage/schema"
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage/sqlite"
 )
 
 func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Fatal("Ошибка загрузки .env файла")
+	// .env необязателен: в контейнере настройки приходят из окружения
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Fatal("Ошибка загрузки .env файла: ", err)
+	}
+
+	be, err := openBackend(os.Getenv("DB_DRIVER"))
+	if err != nil {
+		log.Fatal("Ошибка настройки БД: ", err)
+	}
+	defer be.db.Close()
+
+	// trade-news-bot migrate up|down [N]|status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(be, os.Args[2:]); err != nil {
+			log.Fatal("Migration failed: ", err)
+		}
+		return
 	}
 
 	token := os.Getenv("TELEGRAM_TOKEN")
@@ -20,18 +45,93 @@ func main() {
 		log.Fatal("TELEGRAM_TOKEN не установлен")
 	}
 
-	db, err := storage.ConnectDB()
-	if err != nil {
-		log.Fatal(err)
+	if err := schema.Up(be.db, be.schema); err != nil {
+		log.Fatal("Migration failed: ", err)
 	}
-	defer db.Close()
 
-	if err := storage.Migrate(db); err != nil {
-		log.Fatal("Migration failed:", err)
-	}
+	b := bot.New(token, be.store)
 
-	b := bot.New(token, db)
+	qp, err := quotes.NewFromEnv()
+	if err != nil {
+		log.Fatal("Ошибка настройки котировок:", err)
+	}
+	if qp != nil {
+		b.SetQuoteProvider(qp)
+	}
 
 	go b.StartNewsUpdater()
+	go b.StartOutboxSender()
+	go b.StartAlertChecker()
+	go b.StartCalendarWorker()
+	go b.StartDigestSender()
+	go b.StartRetentionJob()
 	b.Start()
 }
+
+// backend — выбранное хранилище и его миграции
+type backend struct {
+	db     *sql.DB
+	store  *storage.Store
+	schema schema.Dialect
+}
+
+// openBackend подключает хранилище по DB_DRIVER: postgres (по умолчанию) или sqlite
+func openBackend(driver string) (*backend, error) {
+	switch driver {
+	case "", "postgres":
+		cfg, err := postgres.DBConfigFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		db, err := postgres.ConnectDB(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return &backend{db: db, store: postgres.New(db), schema: postgres.Schema}, nil
+
+	case "sqlite":
+		db, err := sqlite.Open(sqlite.PathFromEnv())
+		if err != nil {
+			return nil, err
+		}
+		return &backend{db: db, store: sqlite.New(db), schema: sqlite.Schema}, nil
+	}
+	return nil, fmt.Errorf("неизвестный DB_DRIVER %q: ожидается postgres или sqlite", driver)
+}
+
+func runMigrate(be *backend, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("использование: migrate up|down [N]|status")
+	}
+
+	switch args[0] {
+	case "up":
+		return schema.Up(be.db, be.schema)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("неверное число шагов: %s", args[1])
+			}
+			steps = n
+		}
+		return schema.Down(be.db, be.schema, steps)
+
+	case "status":
+		states, err := schema.Status(be.db, be.schema)
+		if err != nil {
+			return err
+		}
+		for _, st := range states {
+			applied := "pending"
+			if st.AppliedAt != nil {
+				applied = st.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-20s %s\n", st.Version, st.Name, applied)
+		}
+		return nil
+	}
+	return fmt.Errorf("неизвестная команда migrate %s", args[0])
+}
@@ -0,0 +1,117 @@
+package quotes
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileProvider читает котировки из CSV-файла для работы без сети.
+// Формат строки: ticker,price,change,timestamp (RFC3339), строки с # пропускаются.
+// Файл перечитывается, если он изменился.
+type FileProvider struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	quotes  map[string]Quote
+}
+
+func NewFileProvider(path string) (*FileProvider, error) {
+	p := &FileProvider{path: path}
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *FileProvider) Quote(ticker string) (Quote, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.reloadIfChanged(); err != nil {
+		return Quote{}, err
+	}
+	q, ok := p.quotes[strings.ToUpper(ticker)]
+	if !ok {
+		return Quote{}, ErrNotFound
+	}
+	return q, nil
+}
+
+func (p *FileProvider) reloadIfChanged() error {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(p.modTime) {
+		return nil
+	}
+	return p.reload()
+}
+
+func (p *FileProvider) reload() error {
+	f, err := os.Open(p.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	r := csv.NewReader(f)
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	quotes := make(map[string]Quote)
+	for line := 1; ; line++ {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		q, err := parseRecord(rec)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", p.path, line, err)
+		}
+		quotes[q.Ticker] = q
+	}
+
+	p.quotes = quotes
+	p.modTime = info.ModTime()
+	return nil
+}
+
+func parseRecord(rec []string) (Quote, error) {
+	if len(rec) < 2 {
+		return Quote{}, fmt.Errorf("ожидается минимум 2 поля, получено %d", len(rec))
+	}
+	q := Quote{Ticker: strings.ToUpper(strings.TrimSpace(rec[0])), Time: time.Now()}
+
+	var err error
+	if q.Price, err = strconv.ParseFloat(strings.TrimSpace(rec[1]), 64); err != nil {
+		return Quote{}, fmt.Errorf("цена: %w", err)
+	}
+	if len(rec) > 2 && strings.TrimSpace(rec[2]) != "" {
+		if q.Change, err = strconv.ParseFloat(strings.TrimSpace(rec[2]), 64); err != nil {
+			return Quote{}, fmt.Errorf("изменение: %w", err)
+		}
+	}
+	if len(rec) > 3 && strings.TrimSpace(rec[3]) != "" {
+		if q.Time, err = time.Parse(time.RFC3339, strings.TrimSpace(rec[3])); err != nil {
+			return Quote{}, fmt.Errorf("время: %w", err)
+		}
+	}
+	return q, nil
+}
@@ -0,0 +1,59 @@
+package quotes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HTTPProvider получает котировки из HTTP API.
+// Запрос: GET <baseURL>/<TICKER>, ответ — JSON вида
+// {"ticker":"SBER","price":300.5,"change":1.2,"timestamp":"2024-01-02T15:04:05Z"}
+type HTTPProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewHTTPProvider(baseURL string) *HTTPProvider {
+	return &HTTPProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type httpQuote struct {
+	Ticker    string    `json:"ticker"`
+	Price     float64   `json:"price"`
+	Change    float64   `json:"change"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+func (p *HTTPProvider) Quote(ticker string) (Quote, error) {
+	resp, err := p.client.Get(p.baseURL + "/" + url.PathEscape(strings.ToUpper(ticker)))
+	if err != nil {
+		return Quote{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return Quote{}, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Quote{}, fmt.Errorf("quotes api: %s", resp.Status)
+	}
+
+	var hq httpQuote
+	if err := json.NewDecoder(resp.Body).Decode(&hq); err != nil {
+		return Quote{}, err
+	}
+	if hq.Ticker == "" {
+		hq.Ticker = strings.ToUpper(ticker)
+	}
+	if hq.Timestamp.IsZero() {
+		hq.Timestamp = time.Now()
+	}
+	return Quote{Ticker: hq.Ticker, Price: hq.Price, Change: hq.Change, Time: hq.Timestamp}, nil
+}
@@ -0,0 +1,55 @@
+package quotes
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ErrNotFound возвращается, если котировки по тикеру нет
+var ErrNotFound = errors.New("котировка не найдена")
+
+// Quote — последняя цена инструмента
+type Quote struct {
+	Ticker string
+	Price  float64
+	// Change — изменение за день в процентах
+	Change float64
+	Time   time.Time
+}
+
+// QuoteProvider отдаёт последние котировки по тикеру
+type QuoteProvider interface {
+	Quote(ticker string) (Quote, error)
+}
+
+// String форматирует котировку для сообщений: «SBER 300.50 (+1.20%)»
+func (q Quote) String() string {
+	return fmt.Sprintf("%s %.2f (%+.2f%%)", q.Ticker, q.Price, q.Change)
+}
+
+// Emoji — направление движения цены
+func (q Quote) Emoji() string {
+	switch {
+	case q.Change > 0:
+		return "🟢"
+	case q.Change < 0:
+		return "🔴"
+	default:
+		return "⚪️"
+	}
+}
+
+// NewFromEnv создаёт провайдер по переменным окружения:
+// QUOTES_FILE — путь к CSV, QUOTES_URL — базовый URL HTTP API.
+// Возвращает nil, если котировки не настроены
+func NewFromEnv() (QuoteProvider, error) {
+	if path := os.Getenv("QUOTES_FILE"); path != "" {
+		return NewFileProvider(path)
+	}
+	if url := os.Getenv("QUOTES_URL"); url != "" {
+		return NewHTTPProvider(url), nil
+	}
+	return nil, nil
+}
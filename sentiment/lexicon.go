@@ -0,0 +1,63 @@
+package sentiment
+
+// Слова-отрицания
+var negations = map[string]bool{
+	"не": true, "нет": true, "ни": true, "без": true, "вряд": true,
+	"not": true, "no": true, "never": true, "without": true, "nor": true,
+}
+
+// Словарь точных совпадений (короткие слова и английские формы)
+var lexicon = map[string]float64{
+	// english +
+	"up": 0.5, "gain": 1.5, "gains": 1.5, "gained": 1.5, "rise": 1.5, "rises": 1.5,
+	"rose": 1.5, "rising": 1.5, "beat": 2, "beats": 2, "strong": 1.5, "stronger": 1.5,
+	"bullish": 2, "buy": 1, "growth": 1.5, "high": 1, "higher": 1, "win": 1.5, "wins": 1.5,
+	"approve": 1.5, "approved": 1.5, "approves": 1.5, "positive": 1.5,
+	// english -
+	"down": -0.5, "fall": -1.5, "falls": -1.5, "fell": -1.5, "falling": -1.5,
+	"drop": -1.5, "drops": -1.5, "dropped": -1.5, "sink": -2, "sinks": -2, "sank": -2,
+	"slide": -1.5, "slides": -1.5, "slid": -1.5, "loss": -2, "losses": -2,
+	"miss": -2, "misses": -2, "missed": -2, "weak": -1.5, "weaker": -1.5,
+	"bearish": -2, "sell": -1, "selloff": -2.5, "low": -1, "lower": -1, "halt": -2,
+	"halted": -2, "fine": -1.5, "fined": -2, "fraud": -3, "probe": -1.5, "war": -2.5,
+	"risk": -1, "risks": -1, "fear": -2, "fears": -2, "negative": -1.5, "cut": -0.5,
+	"cuts": -0.5,
+
+	// русские короткие слова
+	"рост": 1.5, "рекорд": 1.5, "плюс": 1, "минус": -1, "спад": -2, "риск": -1,
+	"риски": -1, "крах": -3, "обвал": -3, "штраф": -2, "иск": -1.5,
+}
+
+type stem struct {
+	prefix string
+	weight float64
+}
+
+// Основы слов (сравнение по префиксу)
+var stems = []stem{
+	// русский +
+	{"вырос", 2}, {"растет", 1.5}, {"растёт", 1.5}, {"росли", 1.5}, {"подорож", 1.5},
+	{"повыс", 1.5}, {"повыш", 1.5}, {"увелич", 1.5}, {"укреп", 1.5}, {"взлет", 2.5},
+	{"взлёт", 2.5}, {"прибыл", 1.5}, {"рекордн", 2}, {"улучш", 1.5}, {"превыс", 1.5},
+	{"дивиденд", 1.5}, {"выкуп", 1}, {"одобр", 1.5}, {"оптимизм", 2}, {"позитив", 1.5},
+	{"восстанов", 1}, {"отскоч", 1.5}, {"отскок", 1.5}, {"ралли", 2}, {"успеш", 1.5},
+	// русский -
+	{"упал", -2}, {"упад", -2}, {"паден", -2}, {"подешев", -1.5}, {"сниж", -1.5},
+	{"снизи", -1.5}, {"сократ", -1.5}, {"сокращ", -1.5}, {"ослаб", -1.5}, {"обвал", -3},
+	{"рухн", -3}, {"убыт", -2}, {"дефолт", -3}, {"банкрот", -3}, {"санкц", -2},
+	{"штраф", -2}, {"кризис", -2.5}, {"рецесс", -2.5}, {"ухудш", -2}, {"пессимизм", -2},
+	{"негатив", -1.5}, {"запрет", -1.5}, {"отозва", -1.5}, {"приостанов", -2},
+	{"делистинг", -2.5}, {"просел", -1.5}, {"просяд", -1.5}, {"потер", -1.5},
+	{"распрод", -1.5}, {"опасени", -1.5}, {"угроз", -1.5}, {"дефицит", -1},
+	// english +
+	{"surg", 2.5}, {"soar", 2.5}, {"jump", 2}, {"rall", 2}, {"climb", 1.5},
+	{"record", 1.5}, {"upgrad", 2}, {"profit", 1.5}, {"boost", 1.5}, {"rebound", 1.5},
+	{"recover", 1.5}, {"outperform", 2}, {"optimis", 2}, {"dividend", 1},
+	{"buyback", 1.5}, {"expand", 1},
+	// english -
+	{"plung", -3}, {"tumbl", -2.5}, {"slump", -2.5}, {"crash", -3}, {"downgrad", -2},
+	{"default", -3}, {"bankrupt", -3}, {"sanction", -2}, {"lawsuit", -2},
+	{"recession", -2.5}, {"layoff", -2}, {"warn", -1.5}, {"underperform", -2},
+	{"pessimis", -2}, {"crisis", -2.5}, {"delist", -2.5}, {"slash", -1.5},
+	{"inflation", -1}, {"shortfall", -2}, {"investigat", -1.5},
+}
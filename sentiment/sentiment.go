@@ -0,0 +1,109 @@
+package sentiment
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+const (
+	// Strong — порог «сильной» тональности для фильтров
+	Strong = 0.5
+	// Neutral — в пределах ±Neutral заголовок считается нейтральным
+	Neutral = 0.15
+
+	// сколько слов после отрицания меняют знак
+	negationWindow = 3
+	// сглаживание при нормализации суммы в диапазон [-1, 1]
+	alpha = 4.0
+)
+
+// Фильтры тональности, которые может выбрать пользователь
+const (
+	FilterAll      = "all"
+	FilterPositive = "positive"
+	FilterNegative = "negative"
+	FilterStrong   = "strong"
+)
+
+// Filters — допустимые значения фильтра
+var Filters = []string{FilterAll, FilterPositive, FilterNegative, FilterStrong}
+
+// Score оценивает тональность заголовка в диапазоне [-1, 1]
+func Score(text string) float64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+
+	var sum float64
+	negate := 0
+	for _, w := range words {
+		if negations[w] || strings.HasSuffix(w, "n't") {
+			negate = negationWindow
+			continue
+		}
+		if v, ok := lookup(w); ok {
+			if negate > 0 {
+				v = -v
+			}
+			sum += v
+		}
+		if negate > 0 {
+			negate--
+		}
+	}
+	if sum == 0 {
+		return 0
+	}
+	return sum / math.Sqrt(sum*sum+alpha)
+}
+
+// Emoji возвращает индикатор тональности
+func Emoji(score float64) string {
+	switch {
+	case score >= Neutral:
+		return "📈"
+	case score <= -Neutral:
+		return "📉"
+	default:
+		return "➖"
+	}
+}
+
+// Match проверяет, проходит ли оценка через фильтр пользователя
+func Match(filter string, score float64) bool {
+	switch filter {
+	case FilterPositive:
+		return score >= Strong
+	case FilterNegative:
+		return score <= -Strong
+	case FilterStrong:
+		return math.Abs(score) >= Strong
+	default:
+		return true
+	}
+}
+
+// ValidFilter проверяет название фильтра
+func ValidFilter(filter string) bool {
+	for _, f := range Filters {
+		if f == filter {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup ищет слово в словаре: сначала целиком, затем по основе,
+// чтобы «выросли», «выросла», «вырос» давали один результат
+func lookup(word string) (float64, bool) {
+	if v, ok := lexicon[word]; ok {
+		return v, true
+	}
+	for _, s := range stems {
+		if strings.HasPrefix(word, s.prefix) {
+			return s.weight, true
+		}
+	}
+	return 0, false
+}
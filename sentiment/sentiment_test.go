@@ -0,0 +1,91 @@
+package sentiment
+
+import (
+	"math"
+	"testing"
+)
+
+// norm — нормализация суммы весов, как в Score
+func norm(sum float64) float64 {
+	if sum == 0 {
+		return 0
+	}
+	return sum / math.Sqrt(sum*sum+alpha)
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		text string
+		sum  float64
+	}{
+		{"", 0},
+		{"Совет директоров соберётся в среду", 0},
+		// формы слов по основе
+		{"Акции Сбербанка выросли", 2},
+		{"Сбербанк вырос на 3%", 2},
+		{"Акции упали", -2},
+		{"Банкротство и дефолт", -6},
+		{"Gazprom shares surge after record profit", 2.5 + 1.5 + 1.5},
+		{"Shares fell", -1.5},
+		// отрицание меняет знак следующих слов
+		{"Компания не получила прибыль", -1.5},
+		{"Shares did not fall", 1.5},
+		{"Profit didn't beat estimates", 1.5 - 2},
+		{"Без штрафа", 2},
+		// отрицание действует только на negationWindow слов
+		{"Не было никаких сомнений, акции выросли", 2},
+		// смешанный текст
+		{"Прибыль выросла, но акции упали", 1.5 + 2 - 2},
+		{"Rally fades as fears of recession grow", 2 - 2 - 2.5},
+		{"Рост и обвал", 1.5 - 3},
+	}
+	for _, tt := range tests {
+		got := Score(tt.text)
+		if want := norm(tt.sum); math.Abs(got-want) > 1e-9 {
+			t.Errorf("Score(%q) = %.4f, ожидалось %.4f (сумма %g)", tt.text, got, want, tt.sum)
+		}
+		if got < -1 || got > 1 {
+			t.Errorf("Score(%q) = %f вне [-1, 1]", tt.text, got)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		filter string
+		score  float64
+		want   bool
+	}{
+		{FilterAll, 0, true},
+		{"", -0.9, true},
+		{FilterPositive, Strong, true},
+		{FilterPositive, Strong - 0.01, false},
+		{FilterPositive, -0.9, false},
+		{FilterNegative, -Strong, true},
+		{FilterNegative, 0.9, false},
+		{FilterStrong, -0.7, true},
+		{FilterStrong, 0.7, true},
+		{FilterStrong, 0.3, false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.filter, tt.score); got != tt.want {
+			t.Errorf("Match(%q, %g) = %v, ожидалось %v", tt.filter, tt.score, got, tt.want)
+		}
+	}
+}
+
+func TestEmoji(t *testing.T) {
+	tests := map[float64]string{
+		Neutral:         "📈",
+		0.9:             "📈",
+		0:               "➖",
+		Neutral - 0.01:  "➖",
+		-Neutral + 0.01: "➖",
+		-Neutral:        "📉",
+	}
+	for score, want := range tests {
+		if got := Emoji(score); got != want {
+			t.Errorf("Emoji(%g) = %s, ожидалось %s", score, got, want)
+		}
+	}
+}
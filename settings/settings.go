@@ -0,0 +1,176 @@
+// Package settings — реестр настроек бота: тип, значение по умолчанию,
+// проверка и описание каждого ключа
+package settings
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kind — тип значения настройки
+type Kind string
+
+const (
+	KindString Kind = "string"
+	KindInt    Kind = "int"
+	KindBool   Kind = "bool"
+	KindURL    Kind = "url"
+	KindEnum   Kind = "enum"
+)
+
+// Ключи известных настроек
+const (
+	Channel       = "channel"
+	Manual        = "manual"
+	RetentionDays = "retention_days"
+	RetentionMode = "retention_mode"
+)
+
+// Режимы очистки: перенос в архив, удаление или только отчёт
+const (
+	RetentionArchive = "archive"
+	RetentionDelete  = "delete"
+	RetentionDryRun  = "dryrun"
+)
+
+// Setting описывает настройку
+type Setting struct {
+	Key         string
+	Kind        Kind
+	Default     string
+	Description string
+	// Options — допустимые значения для KindEnum
+	Options []string
+	// Min, Max — границы для KindInt
+	Min, Max int
+}
+
+var registry = map[string]Setting{
+	Channel: {
+		Key:         Channel,
+		Kind:        KindURL,
+		Description: "ссылка на канал",
+	},
+	Manual: {
+		Key:         Manual,
+		Kind:        KindURL,
+		Description: "ссылка на инструкцию",
+	},
+	RetentionDays: {
+		Key:         RetentionDays,
+		Kind:        KindInt,
+		Default:     "0",
+		Description: "сколько дней хранить новости, 0 — всегда",
+		Min:         0,
+		Max:         3650,
+	},
+	RetentionMode: {
+		Key:         RetentionMode,
+		Kind:        KindEnum,
+		Default:     RetentionArchive,
+		Description: "что делать со старыми новостями",
+		Options:     []string{RetentionArchive, RetentionDelete, RetentionDryRun},
+	},
+}
+
+// All возвращает известные настройки по алфавиту
+func All() []Setting {
+	list := make([]Setting, 0, len(registry))
+	for _, s := range registry {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	return list
+}
+
+// Lookup ищет настройку по ключу
+func Lookup(key string) (Setting, bool) {
+	s, ok := registry[key]
+	return s, ok
+}
+
+// Parse проверяет значение и приводит его к каноническому виду
+func (s Setting) Parse(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch s.Kind {
+	case KindInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return "", fmt.Errorf("%s: нужно целое число", s.Key)
+		}
+		if n < s.Min || n > s.Max {
+			return "", fmt.Errorf("%s: допустимо от %d до %d", s.Key, s.Min, s.Max)
+		}
+		return strconv.Itoa(n), nil
+	case KindBool:
+		switch strings.ToLower(value) {
+		case "on", "true", "1", "да", "вкл":
+			return "true", nil
+		case "off", "false", "0", "нет", "выкл":
+			return "false", nil
+		}
+		return "", fmt.Errorf("%s: нужно on или off", s.Key)
+	case KindURL:
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "tg") || (u.Host == "" && u.Opaque == "") {
+			return "", fmt.Errorf("%s: нужна ссылка http(s):// или tg://", s.Key)
+		}
+		return value, nil
+	case KindEnum:
+		for _, o := range s.Options {
+			if strings.EqualFold(value, o) {
+				return o, nil
+			}
+		}
+		return "", fmt.Errorf("%s: допустимо %s", s.Key, strings.Join(s.Options, ", "))
+	}
+	if value == "" {
+		return "", fmt.Errorf("%s: пустое значение", s.Key)
+	}
+	return value, nil
+}
+
+// TypeName — тип для подсказок админу
+func (s Setting) TypeName() string {
+	switch s.Kind {
+	case KindEnum:
+		return strings.Join(s.Options, "|")
+	case KindInt:
+		return fmt.Sprintf("число %d..%d", s.Min, s.Max)
+	}
+	return string(s.Kind)
+}
+
+// Values — сохранённые значения с подставленными значениями по умолчанию
+type Values map[string]string
+
+// Resolve дополняет сохранённые значения значениями по умолчанию.
+// Значения, не прошедшие проверку, заменяются значением по умолчанию
+func Resolve(stored map[string]string) Values {
+	v := make(Values, len(registry))
+	for key, s := range registry {
+		v[key] = s.Default
+		if raw, ok := stored[key]; ok {
+			if parsed, err := s.Parse(raw); err == nil {
+				v[key] = parsed
+			}
+		}
+	}
+	return v
+}
+
+func (v Values) String(key string) string {
+	return v[key]
+}
+
+func (v Values) Int(key string) int {
+	n, _ := strconv.Atoi(v[key])
+	return n
+}
+
+func (v Values) Bool(key string) bool {
+	return v[key] == "true"
+}
@@ -0,0 +1,93 @@
+package settings
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	// в реестре нет bool- и строковых настроек, их проверяем на примерах
+	flag := Setting{Key: "flag", Kind: KindBool}
+	text := Setting{Key: "text", Kind: KindString}
+	lookup := func(key string) Setting {
+		s, ok := Lookup(key)
+		if !ok {
+			t.Fatalf("настройки %s нет в реестре", key)
+		}
+		return s
+	}
+
+	tests := []struct {
+		setting Setting
+		in      string
+		want    string
+		ok      bool
+	}{
+		// число с границами 0..3650
+		{lookup(RetentionDays), "30", "30", true},
+		{lookup(RetentionDays), " 0 ", "0", true},
+		{lookup(RetentionDays), "3650", "3650", true},
+		{lookup(RetentionDays), "+7", "7", true},
+		{lookup(RetentionDays), "3651", "", false},
+		{lookup(RetentionDays), "-1", "", false},
+		{lookup(RetentionDays), "7.5", "", false},
+		{lookup(RetentionDays), "неделя", "", false},
+		{lookup(RetentionDays), "", "", false},
+		// режим очистки
+		{lookup(RetentionMode), "archive", RetentionArchive, true},
+		{lookup(RetentionMode), "DELETE", RetentionDelete, true},
+		{lookup(RetentionMode), " DryRun ", RetentionDryRun, true},
+		{lookup(RetentionMode), "drop", "", false},
+		{lookup(RetentionMode), "", "", false},
+		// ссылка на канал
+		{lookup(Channel), "https://t.me/trade_news", "https://t.me/trade_news", true},
+		{lookup(Channel), "http://example.com/news", "http://example.com/news", true},
+		{lookup(Channel), "tg://resolve?domain=trade_news", "tg://resolve?domain=trade_news", true},
+		{lookup(Channel), "@trade_news", "", false},
+		{lookup(Channel), "t.me/trade_news", "", false},
+		{lookup(Channel), "ftp://example.com", "", false},
+		{lookup(Channel), "https://", "", false},
+		{lookup(Channel), "", "", false},
+		{lookup(Manual), "https://telegra.ph/manual", "https://telegra.ph/manual", true},
+		// bool
+		{flag, "on", "true", true},
+		{flag, "Да", "true", true},
+		{flag, "0", "false", true},
+		{flag, "выкл", "false", true},
+		{flag, "maybe", "", false},
+		// строка
+		{text, "  привет ", "привет", true},
+		{text, "   ", "", false},
+	}
+	for _, tt := range tests {
+		got, err := tt.setting.Parse(tt.in)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s.Parse(%q): ошибка %v, ожидалось ok = %v", tt.setting.Key, tt.in, err, tt.ok)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s.Parse(%q) = %q, ожидалось %q", tt.setting.Key, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	v := Resolve(map[string]string{
+		RetentionDays: "99999",
+		RetentionMode: "Delete",
+		Channel:       "https://t.me/trade_news",
+		"unknown":     "x",
+	})
+	if got := v.Int(RetentionDays); got != 0 {
+		t.Errorf("%s = %d, ожидалось значение по умолчанию 0", RetentionDays, got)
+	}
+	if got := v.String(RetentionMode); got != RetentionDelete {
+		t.Errorf("%s = %q, ожидалось %q", RetentionMode, got, RetentionDelete)
+	}
+	if got := v.String(Channel); got != "https://t.me/trade_news" {
+		t.Errorf("%s = %q", Channel, got)
+	}
+	if got := v.String(Manual); got != "" {
+		t.Errorf("%s без значения = %q", Manual, got)
+	}
+	if _, ok := v["unknown"]; ok {
+		t.Error("неизвестный ключ попал в Values")
+	}
+}
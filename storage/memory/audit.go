@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+func (r *Repository) AddAuditEntry(e storage.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.auditSeq++
+	e.ID = r.auditSeq
+	e.CreatedAt = time.Now()
+	r.audit = append(r.audit, e)
+	return nil
+}
+
+func (r *Repository) GetAuditLog(f storage.AuditFilter, page, pageSize int) ([]storage.AuditEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries := r.filterAudit(f)
+	start := (page - 1) * pageSize
+	if start < 0 || start >= len(entries) {
+		return nil, nil
+	}
+	end := start + pageSize
+	if end > len(entries) {
+		end = len(entries)
+	}
+	return entries[start:end], nil
+}
+
+func (r *Repository) CountAuditLog(f storage.AuditFilter) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.filterAudit(f)), nil
+}
+
+// filterAudit — записи по фильтру от новых к старым. Вызывается под r.mu
+func (r *Repository) filterAudit(f storage.AuditFilter) []storage.AuditEntry {
+	var entries []storage.AuditEntry
+	for i := len(r.audit) - 1; i >= 0; i-- {
+		e := r.audit[i]
+		if (f.ActorID == 0 || e.ActorID == f.ActorID) && (f.Action == "" || e.Action == f.Action) {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
@@ -0,0 +1,217 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/calendar"
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+// Ценовые алерты
+
+func (r *Repository) AddPriceAlert(a storage.PriceAlert) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.alertSeq++
+	a.ID = r.alertSeq
+	a.CreatedAt = time.Now()
+	r.alerts = append(r.alerts, &alert{PriceAlert: a})
+	return a.ID, nil
+}
+
+func (r *Repository) DeletePriceAlert(userID, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, a := range r.alerts {
+		if a.ID == id && a.UserID == userID {
+			r.alerts = append(r.alerts[:i], r.alerts[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r *Repository) GetUserPriceAlerts(userID int64) ([]storage.PriceAlert, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var alerts []storage.PriceAlert
+	for _, a := range r.alerts {
+		if a.UserID == userID && !a.triggered {
+			alerts = append(alerts, a.PriceAlert)
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Ticker != alerts[j].Ticker {
+			return alerts[i].Ticker < alerts[j].Ticker
+		}
+		return alerts[i].Level < alerts[j].Level
+	})
+	return alerts, nil
+}
+
+func (r *Repository) GetActivePriceAlerts() ([]storage.PriceAlert, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var alerts []storage.PriceAlert
+	for _, a := range r.alerts {
+		if !a.triggered {
+			alerts = append(alerts, a.PriceAlert)
+		}
+	}
+	return alerts, nil
+}
+
+func (r *Repository) MarkPriceAlertTriggered(id int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, a := range r.alerts {
+		if a.ID == id && !a.triggered {
+			a.triggered = true
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Экономический календарь
+
+func (r *Repository) AddCalendarSource(url string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calSources[url] = true
+	return nil
+}
+
+func (r *Repository) RemoveCalendarSource(url string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for uid, e := range r.events {
+		if e.source == url {
+			delete(r.events, uid)
+		}
+	}
+	delete(r.calSources, url)
+	return nil
+}
+
+func (r *Repository) GetCalendarSources() ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return sortedKeys(r.calSources), nil
+}
+
+func (r *Repository) SaveEvents(source string, events []calendar.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range events {
+		r.events[e.UID] = event{Event: e, source: source}
+	}
+	return nil
+}
+
+func (r *Repository) GetEventsBetween(from, to time.Time) ([]calendar.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []calendar.Event
+	for _, e := range r.events {
+		if !e.Start.Before(from) && e.Start.Before(to) {
+			events = append(events, e.Event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+	return events, nil
+}
+
+func (r *Repository) GetEventCategories() ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cats := make(map[string]bool)
+	for _, e := range r.events {
+		cats[e.Category] = true
+	}
+	return sortedKeys(cats), nil
+}
+
+func (r *Repository) SubscribeCalendar(userID int64, category string, remindBefore int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.calSubs[userID] == nil {
+		r.calSubs[userID] = make(map[string]int)
+	}
+	r.calSubs[userID][category] = remindBefore
+	return nil
+}
+
+func (r *Repository) UnsubscribeCalendar(userID int64, category string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.calSubs[userID][category]; !ok {
+		return false, nil
+	}
+	delete(r.calSubs[userID], category)
+	return true, nil
+}
+
+func (r *Repository) GetCalendarSubscriptions(userID int64) (map[string]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	subs := make(map[string]int)
+	for c, m := range r.calSubs[userID] {
+		subs[c] = m
+	}
+	return subs, nil
+}
+
+func (r *Repository) GetDueEventReminders() ([]storage.EventNotification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	return r.dueEvents(storage.EventNotifyReminder, func(e event, remindBefore int) bool {
+		return !e.AllDay && e.Start.After(now) && !e.Start.Add(-time.Duration(remindBefore)*time.Minute).After(now)
+	}), nil
+}
+
+func (r *Repository) GetDueEventFollowups(after time.Duration) ([]storage.EventNotification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	return r.dueEvents(storage.EventNotifyNews, func(e event, _ int) bool {
+		return !e.AllDay && !e.Start.After(now.Add(-after)) && e.Start.After(now.Add(-24*time.Hour))
+	}), nil
+}
+
+func (r *Repository) MarkEventNotified(userID int64, uid, kind string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := notifyKey{userID, uid, kind}
+	if r.notified[key] {
+		return false, nil
+	}
+	r.notified[key] = true
+	return true, nil
+}
+
+// dueEvents подбирает события по подпискам, ещё не отправленные как kind.
+// Если подходит несколько подписок, берётся та, что напоминает раньше.
+// Вызывается под r.mu
+func (r *Repository) dueEvents(kind string, due func(e event, remindBefore int) bool) []storage.EventNotification {
+	var out []storage.EventNotification
+	for uid, subs := range r.calSubs {
+		for _, e := range r.events {
+			if r.notified[notifyKey{uid, e.UID, kind}] {
+				continue
+			}
+			best, found := 0, false
+			for cat, m := range subs {
+				if (cat == e.Category || cat == storage.AllCategories) && due(e, m) && (!found || m > best) {
+					best, found = m, true
+				}
+			}
+			if found {
+				out = append(out, storage.EventNotification{UserID: uid, Event: e.Event, RemindBefore: best})
+			}
+		}
+	}
+	return out
+}
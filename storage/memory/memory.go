@@ -0,0 +1,778 @@
+// Package memory — хранилище в памяти процесса для разработки и отладки без базы
+package memory
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/calendar"
+	"github.com/FFFFFFFFFFj/trade-news-bot/importance"
+	"github.com/FFFFFFFFFFj/trade-news-bot/sentiment"
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+	"github.com/FFFFFFFFFFj/trade-news-bot/tagger"
+)
+
+// Repository реализует все репозитории storage в памяти
+type Repository struct {
+	mu sync.Mutex
+
+	users         map[int64]*storage.UserProfile
+	sources       map[string]*storage.Source
+	subscriptions map[int64]map[string]bool
+	news          []storage.NewsItem
+	newsByLink    map[string]int // link → индекс в news
+	newsSeq       int64
+	descriptions  map[string]string
+	deferred      map[int64][]string
+	autopost      map[int64][]storage.AutopostSchedule
+	settings      map[string]string
+	settingsLog   []storage.SettingChange
+	aliases       map[string]string
+	watchlist     map[int64]map[string]bool
+	prefs         map[int64]*prefs
+	alerts        []*alert
+	alertSeq      int64
+	calSources    map[string]bool
+	events        map[string]event
+	calSubs       map[int64]map[string]int
+	notified      map[notifyKey]bool
+	rules         map[string]int
+	reads         map[int64]map[string]bool
+	outbox        []*delivery
+	outboxSeq     int64
+	audit         []storage.AuditEntry
+	auditSeq      int64
+}
+
+type delivery struct {
+	id           int64
+	userID       int64
+	link         string
+	status       string
+	attempts     int
+	nextAttempt  time.Time
+	claimedUntil time.Time
+	lastError    string
+	doneAt       time.Time
+}
+
+type prefs struct {
+	sentimentFilter     string
+	showPrice           bool
+	timezone            string
+	importanceThreshold int
+}
+
+type alert struct {
+	storage.PriceAlert
+	triggered bool
+}
+
+type event struct {
+	calendar.Event
+	source string
+}
+
+type notifyKey struct {
+	userID int64
+	uid    string
+	kind   string
+}
+
+// New создаёт пустое хранилище в памяти
+func New() *storage.Store {
+	r := &Repository{
+		users:         make(map[int64]*storage.UserProfile),
+		sources:       make(map[string]*storage.Source),
+		subscriptions: make(map[int64]map[string]bool),
+		newsByLink:    make(map[string]int),
+		descriptions:  make(map[string]string),
+		deferred:      make(map[int64][]string),
+		autopost:      make(map[int64][]storage.AutopostSchedule),
+		settings:      make(map[string]string),
+		aliases:       make(map[string]string),
+		watchlist:     make(map[int64]map[string]bool),
+		prefs:         make(map[int64]*prefs),
+		calSources:    make(map[string]bool),
+		events:        make(map[string]event),
+		calSubs:       make(map[int64]map[string]int),
+		notified:      make(map[notifyKey]bool),
+		rules:         make(map[string]int),
+		reads:         make(map[int64]map[string]bool),
+	}
+	return &storage.Store{
+		Users:         r,
+		Sources:       r,
+		Subscriptions: r,
+		News:          r,
+		Autopost:      r,
+		Settings:      r,
+		Tags:          r,
+		Watchlist:     r,
+		Prefs:         r,
+		Alerts:        r,
+		Calendar:      r,
+		Importance:    r,
+		Retention:     r,
+		Reads:         r,
+		Outbox:        r,
+		Audit:         r,
+		Stats:         r,
+	}
+}
+
+// userPrefs возвращает настройки пользователя, создавая их при необходимости.
+// Вызывается под r.mu
+func (r *Repository) userPrefs(userID int64) *prefs {
+	p, ok := r.prefs[userID]
+	if !ok {
+		p = &prefs{sentimentFilter: sentiment.FilterAll, timezone: storage.DefaultTimezone}
+		r.prefs[userID] = p
+	}
+	return p
+}
+
+// Пользователи
+
+func (r *Repository) TouchUser(p storage.UserProfile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	u, ok := r.users[p.ID]
+	if !ok {
+		u = &storage.UserProfile{ID: p.ID, JoinedAt: now, Status: storage.UserActive}
+		r.users[p.ID] = u
+	}
+	u.Username, u.FirstName, u.LastName = p.Username, p.FirstName, p.LastName
+	if p.LanguageCode != "" {
+		u.LanguageCode = p.LanguageCode
+	}
+	u.LastSeenAt = now
+	u.Status = storage.UserActive
+	return nil
+}
+
+func (r *Repository) EnsureUser(id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[id]; !ok {
+		r.users[id] = &storage.UserProfile{ID: id, JoinedAt: time.Now(), Status: storage.UserActive}
+	}
+	return nil
+}
+
+func (r *Repository) DeactivateUser(id int64, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if u, ok := r.users[id]; ok {
+		u.Status = status
+	}
+	for _, d := range r.outbox {
+		if d.userID == id && d.status == deliveryPending {
+			d.status = storage.DeliverySkipped
+			d.lastError = "user " + status
+		}
+	}
+	return nil
+}
+
+func (r *Repository) MigrateUserChat(oldID, newID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if u, ok := r.users[oldID]; ok {
+		if _, exists := r.users[newID]; !exists {
+			u.ID = newID
+			r.users[newID] = u
+		}
+		delete(r.users, oldID)
+	}
+	// как и в SQL: при совпадении ключа остаются данные нового чата
+	mergeUser(r.subscriptions, oldID, newID)
+	mergeUser(r.watchlist, oldID, newID)
+	mergeUser(r.calSubs, oldID, newID)
+	mergeUser(r.reads, oldID, newID)
+	moveUser(r.prefs, oldID, newID)
+	for _, link := range r.deferred[oldID] {
+		if !slices.Contains(r.deferred[newID], link) {
+			r.deferred[newID] = append(r.deferred[newID], link)
+		}
+	}
+	delete(r.deferred, oldID)
+	for _, s := range r.autopost[oldID] {
+		taken := false
+		for _, own := range r.autopost[newID] {
+			taken = taken || own.TimeOfDay == s.TimeOfDay
+		}
+		if !taken {
+			s.UserID = newID
+			r.autopost[newID] = append(r.autopost[newID], s)
+		}
+	}
+	delete(r.autopost, oldID)
+	for _, a := range r.alerts {
+		if a.UserID == oldID {
+			a.UserID = newID
+		}
+	}
+	for key := range r.notified {
+		if key.userID == oldID {
+			delete(r.notified, key)
+			key.userID = newID
+			r.notified[key] = true
+		}
+	}
+	queued := make(map[string]bool)
+	for _, d := range r.outbox {
+		if d.userID == newID {
+			queued[d.link] = true
+		}
+	}
+	outbox := r.outbox[:0]
+	for _, d := range r.outbox {
+		if d.userID == oldID {
+			if queued[d.link] {
+				continue
+			}
+			d.userID = newID
+		}
+		outbox = append(outbox, d)
+	}
+	r.outbox = outbox
+	return nil
+}
+
+func (r *Repository) DeleteUser(id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.users, id)
+	delete(r.subscriptions, id)
+	delete(r.deferred, id)
+	delete(r.autopost, id)
+	delete(r.watchlist, id)
+	delete(r.prefs, id)
+	delete(r.calSubs, id)
+	delete(r.reads, id)
+	alerts := r.alerts[:0]
+	for _, a := range r.alerts {
+		if a.UserID != id {
+			alerts = append(alerts, a)
+		}
+	}
+	r.alerts = alerts
+	for key := range r.notified {
+		if key.userID == id {
+			delete(r.notified, key)
+		}
+	}
+	outbox := r.outbox[:0]
+	for _, d := range r.outbox {
+		if d.userID != id {
+			outbox = append(outbox, d)
+		}
+	}
+	r.outbox = outbox
+	return nil
+}
+
+// mergeUser переносит в набор newID элементы oldID, которых там ещё нет
+func mergeUser[K comparable, V any](m map[int64]map[K]V, oldID, newID int64) {
+	old, ok := m[oldID]
+	if !ok {
+		return
+	}
+	if m[newID] == nil {
+		m[newID] = make(map[K]V, len(old))
+	}
+	for k, v := range old {
+		if _, exists := m[newID][k]; !exists {
+			m[newID][k] = v
+		}
+	}
+	delete(m, oldID)
+}
+
+// moveUser переносит данные пользователя на новый ID, если там ещё пусто
+func moveUser[V any](m map[int64]V, oldID, newID int64) {
+	v, ok := m[oldID]
+	if !ok {
+		return
+	}
+	if _, exists := m[newID]; !exists {
+		m[newID] = v
+	}
+	delete(m, oldID)
+}
+
+// active — можно ли писать пользователю. Пользователи без профиля считаются активными.
+// Вызывается под r.mu
+func (r *Repository) active(userID int64) bool {
+	u, ok := r.users[userID]
+	return !ok || u.Status == storage.UserActive
+}
+
+func (r *Repository) GetUser(id int64) (storage.UserProfile, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[id]
+	if !ok {
+		return storage.UserProfile{}, false, nil
+	}
+	return *u, true, nil
+}
+
+func (r *Repository) FindUsers(query string, limit int) ([]storage.UserProfile, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	query = strings.ToLower(strings.TrimPrefix(query, "@"))
+	var found []storage.UserProfile
+	for _, u := range r.users {
+		if strings.Contains(strings.ToLower(u.Username), query) || strings.Contains(strings.ToLower(u.FirstName+" "+u.LastName), query) {
+			found = append(found, *u)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if !found[i].LastSeenAt.Equal(found[j].LastSeenAt) {
+			return found[i].LastSeenAt.After(found[j].LastSeenAt)
+		}
+		return found[i].ID < found[j].ID
+	})
+	if len(found) > limit {
+		found = found[:limit]
+	}
+	return found, nil
+}
+
+func (r *Repository) GetReachableUsers() ([]int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	users := make([]int64, 0, len(r.users))
+	for id, u := range r.users {
+		if u.Status == storage.UserActive {
+			users = append(users, id)
+		}
+	}
+	return users, nil
+}
+
+func (r *Repository) GetUsersCount() (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.users), nil
+}
+
+func (r *Repository) CountUsersSeenSince(t time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := 0
+	for _, u := range r.users {
+		if !u.LastSeenAt.Before(t) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *Repository) CountUsersJoinedSince(t time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := 0
+	for _, u := range r.users {
+		if !u.JoinedAt.Before(t) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *Repository) GetUserLanguages() (map[string]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	langs := make(map[string]int)
+	for _, u := range r.users {
+		langs[u.LanguageCode]++
+	}
+	return langs, nil
+}
+
+func (r *Repository) GetActiveUsersCount() (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := 0
+	for _, subs := range r.subscriptions {
+		if len(subs) > 0 {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Источники
+
+func (r *Repository) AddSource(url string, addedBy int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.sources[url]; !ok {
+		r.sources[url] = &storage.Source{URL: url, AddedBy: addedBy, AddedAt: time.Now()}
+	}
+	return nil
+}
+
+func (r *Repository) RemoveSource(url string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sources, url)
+	for _, subs := range r.subscriptions {
+		delete(subs, url)
+	}
+	return nil
+}
+
+func (r *Repository) GetAllSources() ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var sources []string
+	for url := range r.sources {
+		sources = append(sources, url)
+	}
+	sort.Strings(sources)
+	return sources, nil
+}
+
+func (r *Repository) GetSources() ([]storage.Source, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sources := make([]storage.Source, 0, len(r.sources))
+	for _, s := range r.sources {
+		sources = append(sources, *s)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		a, b := sources[i], sources[j]
+		if a.SortOrder != b.SortOrder {
+			return a.SortOrder > b.SortOrder
+		}
+		if (a.Title == "") != (b.Title == "") {
+			return a.Title != ""
+		}
+		if a.Title != b.Title {
+			return a.Title < b.Title
+		}
+		return a.URL < b.URL
+	})
+	return sources, nil
+}
+
+func (r *Repository) GetSource(url string) (storage.Source, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sources[url]
+	if !ok {
+		return storage.Source{}, false, nil
+	}
+	return *s, true, nil
+}
+
+func (r *Repository) UpdateSource(s storage.Source) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cur, ok := r.sources[s.URL]
+	if !ok {
+		return false, nil
+	}
+	cur.Title = s.Title
+	cur.Description = s.Description
+	cur.Category = s.Category
+	cur.Language = s.Language
+	cur.SortOrder = s.SortOrder
+	return true, nil
+}
+
+func (r *Repository) FillSourceMeta(url, title, description, language string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sources[url]
+	if !ok {
+		return nil
+	}
+	if s.Title == "" {
+		s.Title = title
+	}
+	if s.Description == "" {
+		s.Description = description
+	}
+	if s.Language == "" {
+		s.Language = language
+	}
+	return nil
+}
+
+func (r *Repository) SetSourcePriority(url string, priority int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sources[url]
+	if !ok {
+		return false, nil
+	}
+	s.Priority = priority
+	return true, nil
+}
+
+func (r *Repository) GetSourcePriorities() (map[string]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	priorities := make(map[string]int, len(r.sources))
+	for url, s := range r.sources {
+		priorities[url] = s.Priority
+	}
+	return priorities, nil
+}
+
+// Подписки
+
+func (r *Repository) Subscribe(userID int64, url string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.sources[url]; !ok {
+		return false, fmt.Errorf("источник %s не найден", url)
+	}
+	subs := r.subscriptions[userID]
+	if subs == nil {
+		subs = make(map[string]bool)
+		r.subscriptions[userID] = subs
+	}
+	if subs[url] {
+		return false, nil
+	}
+	subs[url] = true
+	return true, nil
+}
+
+func (r *Repository) Unsubscribe(userID int64, url string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.subscriptions[userID][url] {
+		return false, nil
+	}
+	delete(r.subscriptions[userID], url)
+	return true, nil
+}
+
+func (r *Repository) GetUserSubscriptions(userID int64) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return sortedKeys(r.subscriptions[userID]), nil
+}
+
+func (r *Repository) GetUserSubscriptionCount(userID int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.subscriptions[userID]), nil
+}
+
+// Авторассылка
+
+func (r *Repository) SetUserAutopost(userID int64, schedules []storage.AutopostSchedule) error {
+	if err := storage.ValidateAutopost(schedules); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saved := make([]storage.AutopostSchedule, len(schedules))
+	for i, s := range schedules {
+		s.UserID = userID
+		saved[i] = s
+	}
+	sort.Slice(saved, func(i, j int) bool { return saved[i].TimeOfDay < saved[j].TimeOfDay })
+	r.autopost[userID] = saved
+	return nil
+}
+
+func (r *Repository) GetUserAutopost(userID int64) ([]storage.AutopostSchedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]storage.AutopostSchedule(nil), r.autopost[userID]...), nil
+}
+
+func (r *Repository) GetEnabledAutoposts() ([]storage.AutopostSchedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []storage.AutopostSchedule
+	for userID, schedules := range r.autopost {
+		if !r.active(userID) {
+			continue
+		}
+		for _, s := range schedules {
+			if s.Enabled {
+				result = append(result, s)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (r *Repository) GetAutopostUsersCount() (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := 0
+	for _, schedules := range r.autopost {
+		for _, s := range schedules {
+			if s.Enabled {
+				count++
+				break
+			}
+		}
+	}
+	return count, nil
+}
+
+// Настройки
+
+func (r *Repository) SetSetting(key, value string, adminID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.settingsLog = append(r.settingsLog, storage.SettingChange{
+		Key:       key,
+		OldValue:  r.settings[key],
+		NewValue:  value,
+		AdminID:   adminID,
+		ChangedAt: time.Now(),
+	})
+	r.settings[key] = value
+	return nil
+}
+
+func (r *Repository) GetSetting(key string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	value, ok := r.settings[key]
+	if !ok {
+		// как и в PostgreSQL-реализации
+		return "", sql.ErrNoRows
+	}
+	return value, nil
+}
+
+func (r *Repository) GetAllSettings() (map[string]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	settings := make(map[string]string, len(r.settings))
+	for k, v := range r.settings {
+		settings[k] = v
+	}
+	return settings, nil
+}
+
+func (r *Repository) GetSettingHistory(key string, limit int) ([]storage.SettingChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var history []storage.SettingChange
+	for i := len(r.settingsLog) - 1; i >= 0 && len(history) < limit; i-- {
+		if r.settingsLog[i].Key == key {
+			history = append(history, r.settingsLog[i])
+		}
+	}
+	return history, nil
+}
+
+// Алиасы тикеров
+
+func (r *Repository) AddTagAlias(alias, ticker string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.aliases[strings.ToLower(strings.TrimSpace(alias))] = tagger.NormalizeTicker(ticker)
+	return nil
+}
+
+func (r *Repository) RemoveTagAlias(alias string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.aliases, strings.ToLower(strings.TrimSpace(alias)))
+	return nil
+}
+
+func (r *Repository) GetTagAliases() (map[string]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	aliases := make(map[string]string, len(r.aliases))
+	for a, t := range r.aliases {
+		aliases[a] = t
+	}
+	return aliases, nil
+}
+
+// Watchlist
+
+func (r *Repository) AddToWatchlist(userID int64, ticker string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.watchlist[userID] == nil {
+		r.watchlist[userID] = make(map[string]bool)
+	}
+	r.watchlist[userID][ticker] = true
+	return nil
+}
+
+func (r *Repository) RemoveFromWatchlist(userID int64, ticker string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.watchlist[userID][ticker] {
+		return false, nil
+	}
+	delete(r.watchlist[userID], ticker)
+	return true, nil
+}
+
+func (r *Repository) GetWatchlist(userID int64) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return sortedKeys(r.watchlist[userID]), nil
+}
+
+// Правила важности
+
+func (r *Repository) SetImportanceRule(keyword string, weight int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules[importance.NormalizeKeyword(keyword)] = weight
+	return nil
+}
+
+func (r *Repository) RemoveImportanceRule(keyword string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keyword = importance.NormalizeKeyword(keyword)
+	if _, ok := r.rules[keyword]; !ok {
+		return false, nil
+	}
+	delete(r.rules, keyword)
+	return true, nil
+}
+
+func (r *Repository) GetImportanceRules() ([]importance.Rule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var rules []importance.Rule
+	for k, w := range r.rules {
+		rules = append(rules, importance.Rule{Keyword: k, Weight: w})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Weight != rules[j].Weight {
+			return rules[i].Weight > rules[j].Weight
+		}
+		return rules[i].Keyword < rules[j].Keyword
+	})
+	return rules, nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,255 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/sentiment"
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+	"github.com/FFFFFFFFFFj/trade-news-bot/tagger"
+)
+
+func (r *Repository) KnownLinks(links []string) (map[string]bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	known := make(map[string]bool)
+	for _, link := range links {
+		if _, ok := r.newsByLink[link]; ok {
+			known[link] = true
+		}
+	}
+	return known, nil
+}
+
+func (r *Repository) SaveNews(n storage.NewsItem, description string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.newsByLink[n.Link]; ok {
+		return false, nil
+	}
+	r.newsSeq++
+	n.ID = r.newsSeq
+	n.Tags = append([]string(nil), n.Tags...)
+	sort.Strings(n.Tags)
+	r.newsByLink[n.Link] = len(r.news)
+	r.news = append(r.news, n)
+	r.descriptions[n.Link] = description
+	for _, uid := range r.recipients(n) {
+		r.enqueue(uid, n.Link)
+	}
+	return true, nil
+}
+
+// recipients — подписчики источника и пользователи с тикером новости в watchlist.
+// Вызывается под r.mu
+func (r *Repository) recipients(n storage.NewsItem) []int64 {
+	seen := make(map[int64]bool)
+	var users []int64
+	add := func(uid int64) {
+		if !seen[uid] && r.active(uid) {
+			seen[uid] = true
+			users = append(users, uid)
+		}
+	}
+	for uid, subs := range r.subscriptions {
+		if subs[n.Source] {
+			add(uid)
+		}
+	}
+	for uid, tickers := range r.watchlist {
+		for _, t := range n.Tags {
+			if tickers[t] {
+				add(uid)
+				break
+			}
+		}
+	}
+	return users
+}
+
+func (r *Repository) GetCoverage(source string, tags []string) (int, error) {
+	if len(tags) == 0 {
+		return 0, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	since := time.Now().Add(-storage.CoverageWindow)
+	others := make(map[string]bool)
+	for _, n := range r.news {
+		if n.Source != source && n.PubDate.After(since) && hasAnyTag(n, tags) {
+			others[n.Source] = true
+		}
+	}
+	return len(others), nil
+}
+
+func (r *Repository) CountLatestNewsForUser(userID int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.userNews(userID)), nil
+}
+
+func (r *Repository) GetLatestNewsForUser(userID int64, cursor storage.NewsCursor, dir storage.PageDirection, limit int) ([]storage.NewsItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := r.userNews(userID)
+
+	// items отсортированы от новых к старым
+	var page []storage.NewsItem
+	switch dir {
+	case storage.PageAt, storage.PageAfter:
+		for _, n := range items {
+			if older(n.Cursor(), cursor) || (dir == storage.PageAt && n.ID == cursor.ID && n.PubDate.Equal(cursor.PubDate)) {
+				page = append(page, n)
+			}
+		}
+	case storage.PageBefore:
+		for _, n := range items {
+			if older(cursor, n.Cursor()) {
+				page = append(page, n)
+			}
+		}
+		if len(page) > limit {
+			page = page[len(page)-limit:]
+		}
+		return page, nil
+	case storage.PageLast:
+		if len(items) > limit {
+			items = items[len(items)-limit:]
+		}
+		return items, nil
+	default:
+		page = items
+	}
+	if len(page) > limit {
+		page = page[:limit]
+	}
+	return page, nil
+}
+
+func (r *Repository) GetNewsBetweenForUser(userID int64, from, to time.Time, limit int) ([]storage.NewsItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var items []storage.NewsItem
+	for _, n := range r.news {
+		if r.subscriptions[userID][n.Source] && !n.PubDate.Before(from) && !n.PubDate.After(to) {
+			items = append(items, n)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].PubDate.Before(items[j].PubDate) })
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func (r *Repository) GetNewsTags(link string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i, ok := r.newsByLink[link]
+	if !ok {
+		return nil, nil
+	}
+	return append([]string(nil), r.news[i].Tags...), nil
+}
+
+func (r *Repository) GetNewsByTag(ticker string, limit int) ([]storage.NewsItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ticker = tagger.NormalizeTicker(ticker)
+	var items []storage.NewsItem
+	for _, n := range r.news {
+		if hasAnyTag(n, []string{ticker}) {
+			items = append(items, n)
+		}
+	}
+	sortByDateDesc(items)
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func (r *Repository) DeferNews(userID int64, link string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, l := range r.deferred[userID] {
+		if l == link {
+			return nil
+		}
+	}
+	r.deferred[userID] = append(r.deferred[userID], link)
+	return nil
+}
+
+func (r *Repository) PopDeferredNews(userID int64) ([]storage.NewsItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var items []storage.NewsItem
+	for _, link := range r.deferred[userID] {
+		if i, ok := r.newsByLink[link]; ok {
+			items = append(items, r.news[i])
+		}
+	}
+	delete(r.deferred, userID)
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Importance != items[j].Importance {
+			return items[i].Importance > items[j].Importance
+		}
+		return items[i].PubDate.After(items[j].PubDate)
+	})
+	return items, nil
+}
+
+// userNews — новости из подписок пользователя с учётом фильтра тональности,
+// от новых к старым. Вызывается под r.mu
+func (r *Repository) userNews(userID int64) []storage.NewsItem {
+	filter := sentiment.FilterAll
+	if p, ok := r.prefs[userID]; ok {
+		filter = p.sentimentFilter
+	}
+	var items []storage.NewsItem
+	for _, n := range r.news {
+		if !r.subscriptions[userID][n.Source] || !sentiment.Match(filter, n.Sentiment) {
+			continue
+		}
+		items = append(items, n)
+	}
+	sortByDateDesc(items)
+	return items
+}
+
+// older — идёт ли a после b в порядке от новых к старым
+func older(a, b storage.NewsCursor) bool {
+	if !a.PubDate.Equal(b.PubDate) {
+		return a.PubDate.Before(b.PubDate)
+	}
+	return a.ID < b.ID
+}
+
+func hasAnyTag(n storage.NewsItem, tags []string) bool {
+	for _, t := range n.Tags {
+		for _, want := range tags {
+			if t == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sortByDateDesc(items []storage.NewsItem) {
+	sort.SliceStable(items, func(i, j int) bool { return older(items[j].Cursor(), items[i].Cursor()) })
+}
+
+func paginate(items []storage.NewsItem, page, pageSize int) []storage.NewsItem {
+	offset := (page - 1) * pageSize
+	if offset < 0 || offset >= len(items) {
+		return nil
+	}
+	end := offset + pageSize
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[offset:end]
+}
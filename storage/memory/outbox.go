@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+// Статус доставки, ожидающей отправки
+const deliveryPending = "pending"
+
+// enqueue ставит новость в outbox, если её ещё не было у пользователя. Вызывается под r.mu
+func (r *Repository) enqueue(userID int64, link string) {
+	for _, d := range r.outbox {
+		if d.userID == userID && d.link == link {
+			return
+		}
+	}
+	r.outboxSeq++
+	r.outbox = append(r.outbox, &delivery{
+		id:          r.outboxSeq,
+		userID:      userID,
+		link:        link,
+		status:      deliveryPending,
+		nextAttempt: time.Now(),
+	})
+}
+
+func (r *Repository) ClaimDeliveries(limit int, lease time.Duration) ([]storage.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	var claimed []storage.Delivery
+	for _, d := range r.outbox {
+		if len(claimed) >= limit {
+			break
+		}
+		if d.status != deliveryPending || d.nextAttempt.After(now) || d.claimedUntil.After(now) {
+			continue
+		}
+		i, ok := r.newsByLink[d.link]
+		if !ok {
+			continue
+		}
+		d.claimedUntil = now.Add(lease)
+		claimed = append(claimed, storage.Delivery{
+			ID:       d.id,
+			UserID:   d.userID,
+			News:     r.news[i],
+			Attempts: d.attempts,
+			Read:     r.reads[d.userID][d.link],
+		})
+	}
+	return claimed, nil
+}
+
+func (r *Repository) CompleteDelivery(id int64, status, lastError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if d := r.delivery(id); d != nil {
+		d.status = status
+		d.lastError = lastError
+		d.claimedUntil = time.Time{}
+		d.doneAt = time.Now()
+	}
+	return nil
+}
+
+func (r *Repository) RetryDelivery(id int64, next time.Time, lastError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if d := r.delivery(id); d != nil {
+		d.attempts++
+		d.nextAttempt = next
+		d.lastError = lastError
+		d.claimedUntil = time.Time{}
+	}
+	return nil
+}
+
+func (r *Repository) CountPendingDeliveries() (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := 0
+	for _, d := range r.outbox {
+		if d.status == deliveryPending {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// delivery ищет доставку по ID. Вызывается под r.mu
+func (r *Repository) delivery(id int64) *delivery {
+	for _, d := range r.outbox {
+		if d.id == id {
+			return d
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package memory
+
+import "github.com/FFFFFFFFFFj/trade-news-bot/sentiment"
+
+func (r *Repository) SetSentimentFilter(userID int64, filter string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.userPrefs(userID).sentimentFilter = filter
+	return nil
+}
+
+func (r *Repository) GetSentimentFilter(userID int64) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.userPrefs(userID).sentimentFilter, nil
+}
+
+func (r *Repository) GetSentimentFilters() (map[int64]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	filters := make(map[int64]string)
+	for uid, p := range r.prefs {
+		if p.sentimentFilter != sentiment.FilterAll {
+			filters[uid] = p.sentimentFilter
+		}
+	}
+	return filters, nil
+}
+
+func (r *Repository) SetShowPrice(userID int64, show bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.userPrefs(userID).showPrice = show
+	return nil
+}
+
+func (r *Repository) GetShowPrice(userID int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.userPrefs(userID).showPrice, nil
+}
+
+func (r *Repository) GetShowPriceUsers() (map[int64]bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	users := make(map[int64]bool)
+	for uid, p := range r.prefs {
+		if p.showPrice {
+			users[uid] = true
+		}
+	}
+	return users, nil
+}
+
+func (r *Repository) SetTimezone(userID int64, tz string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.userPrefs(userID).timezone = tz
+	return nil
+}
+
+func (r *Repository) GetTimezone(userID int64) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.userPrefs(userID).timezone, nil
+}
+
+func (r *Repository) SetImportanceThreshold(userID int64, threshold int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.userPrefs(userID).importanceThreshold = threshold
+	return nil
+}
+
+func (r *Repository) GetImportanceThreshold(userID int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.userPrefs(userID).importanceThreshold, nil
+}
+
+func (r *Repository) GetImportanceThresholds() (map[int64]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	thresholds := make(map[int64]int)
+	for uid, p := range r.prefs {
+		if p.importanceThreshold != 0 {
+			thresholds[uid] = p.importanceThreshold
+		}
+	}
+	return thresholds, nil
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"sort"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+func (r *Repository) MarkNewsRead(userID int64, links []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, link := range links {
+		if _, ok := r.newsByLink[link]; ok {
+			r.markRead(userID, link)
+		}
+	}
+	return nil
+}
+
+func (r *Repository) MarkAllNewsRead(userID int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	marked := 0
+	for _, n := range r.news {
+		if r.subscriptions[userID][n.Source] && r.markRead(userID, n.Link) {
+			marked++
+		}
+	}
+	return marked, nil
+}
+
+func (r *Repository) CountUnreadNewsForUser(userID int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.unreadNews(userID)), nil
+}
+
+func (r *Repository) GetUnreadNewsForUser(userID int64, limit int) ([]storage.NewsItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := r.unreadNews(userID)
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func (r *Repository) GetReadNews(userID int64) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	links := make([]string, 0, len(r.reads[userID]))
+	for link := range r.reads[userID] {
+		links = append(links, link)
+	}
+	sort.Strings(links)
+	return links, nil
+}
+
+// markRead возвращает false, если новость уже была прочитана. Вызывается под r.mu
+func (r *Repository) markRead(userID int64, link string) bool {
+	if r.reads[userID] == nil {
+		r.reads[userID] = make(map[string]bool)
+	}
+	if r.reads[userID][link] {
+		return false
+	}
+	r.reads[userID][link] = true
+	return true
+}
+
+// unreadNews — непрочитанные новости из подписок. Вызывается под r.mu
+func (r *Repository) unreadNews(userID int64) []storage.NewsItem {
+	var items []storage.NewsItem
+	for _, n := range r.userNews(userID) {
+		if !r.reads[userID][n.Link] {
+			items = append(items, n)
+		}
+	}
+	return items
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+// В памяти архив не ведётся: archive равносилен удалению
+func (r *Repository) PruneNews(before time.Time, archive, dryRun bool) (storage.RetentionStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var stats storage.RetentionStats
+	var kept []storage.NewsItem
+	for _, n := range r.news {
+		if n.PubDate.Before(before) {
+			stats.News++
+			for _, read := range r.reads {
+				if read[n.Link] {
+					stats.ReadMarkers++
+				}
+			}
+			continue
+		}
+		kept = append(kept, n)
+	}
+	if dryRun || stats.News == 0 {
+		return stats, nil
+	}
+
+	r.news = kept
+	r.newsByLink = make(map[string]int, len(kept))
+	for i, n := range kept {
+		r.newsByLink[n.Link] = i
+	}
+	for link := range r.descriptions {
+		if _, ok := r.newsByLink[link]; !ok {
+			delete(r.descriptions, link)
+		}
+	}
+	for _, read := range r.reads {
+		for link := range read {
+			if _, ok := r.newsByLink[link]; !ok {
+				delete(read, link)
+			}
+		}
+	}
+	var outbox []*delivery
+	for _, d := range r.outbox {
+		if _, ok := r.newsByLink[d.link]; ok {
+			outbox = append(outbox, d)
+		}
+	}
+	r.outbox = outbox
+	for uid, links := range r.deferred {
+		var left []string
+		for _, l := range links {
+			if _, ok := r.newsByLink[l]; ok {
+				left = append(left, l)
+			}
+		}
+		r.deferred[uid] = left
+	}
+	return stats, nil
+}
+
+func (r *Repository) Analyze() error {
+	return nil
+}
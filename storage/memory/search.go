@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+func (r *Repository) SearchNews(q storage.SearchQuery, page, pageSize int) ([]storage.NewsItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return paginate(r.search(q), page, pageSize), nil
+}
+
+func (r *Repository) CountSearchNews(q storage.SearchQuery) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.search(q)), nil
+}
+
+// search ищет слова запроса как подстроки без учёта регистра; слова с минусом
+// исключаются. Выше — новости, у которых больше совпадений в заголовке.
+// Вызывается под r.mu
+func (r *Repository) search(q storage.SearchQuery) []storage.NewsItem {
+	var include, exclude []string
+	for _, w := range strings.Fields(strings.ToLower(q.Text)) {
+		if strings.HasPrefix(w, "-") {
+			if w = strings.TrimLeft(w, "-"); w != "" {
+				exclude = append(exclude, w)
+			}
+			continue
+		}
+		include = append(include, w)
+	}
+	if len(include) == 0 {
+		return nil
+	}
+
+	type hit struct {
+		n     storage.NewsItem
+		score int
+	}
+	var hits []hit
+	for _, n := range r.news {
+		if q.Source != "" && !strings.Contains(n.Source, q.Source) {
+			continue
+		}
+		if (!q.From.IsZero() && n.PubDate.Before(q.From)) || (!q.To.IsZero() && !n.PubDate.Before(q.To)) {
+			continue
+		}
+		title := strings.ToLower(n.Title)
+		text := title + "\n" + strings.ToLower(r.descriptions[n.Link])
+		ok, score := true, 0
+		for _, w := range include {
+			if !strings.Contains(text, w) {
+				ok = false
+				break
+			}
+			if strings.Contains(title, w) {
+				score++
+			}
+		}
+		for _, w := range exclude {
+			if strings.Contains(text, w) {
+				ok = false
+			}
+		}
+		if ok {
+			hits = append(hits, hit{n, score})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return hits[i].n.PubDate.After(hits[j].n.PubDate)
+	})
+
+	items := make([]storage.NewsItem, len(hits))
+	for i, h := range hits {
+		items[i] = h.n
+	}
+	return items
+}
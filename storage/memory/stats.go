@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+func (r *Repository) CountNewsByDay(since time.Time) ([]storage.DayCount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := make(dayCounter)
+	for _, n := range r.news {
+		if !n.PubDate.Before(since) {
+			counts.add(n.PubDate, n.Source)
+		}
+	}
+	return counts.list(), nil
+}
+
+func (r *Repository) CountPushesByDay(since time.Time) ([]storage.DayCount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := make(dayCounter)
+	for _, d := range r.outbox {
+		if d.status == storage.DeliverySent && !d.doneAt.Before(since) {
+			counts.add(d.doneAt, "")
+		}
+	}
+	return counts.list(), nil
+}
+
+func (r *Repository) CountNewUsersByDay(since time.Time) ([]storage.DayCount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := make(dayCounter)
+	for _, u := range r.users {
+		if !u.JoinedAt.Before(since) {
+			counts.add(u.JoinedAt, "")
+		}
+	}
+	return counts.list(), nil
+}
+
+type dayKey struct {
+	day time.Time
+	key string
+}
+
+// dayCounter считает записи по дням (UTC) и ключам
+type dayCounter map[dayKey]int
+
+func (c dayCounter) add(t time.Time, key string) {
+	t = t.UTC()
+	c[dayKey{time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), key}]++
+}
+
+func (c dayCounter) list() []storage.DayCount {
+	counts := make([]storage.DayCount, 0, len(c))
+	for k, n := range c {
+		counts = append(counts, storage.DayCount{Day: k.day, Key: k.key, Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if !counts[i].Day.Equal(counts[j].Day) {
+			return counts[i].Day.Before(counts[j].Day)
+		}
+		return counts[i].Key < counts[j].Key
+	})
+	return counts
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import "github.com/FFFFFFFFFFj/trade-news-bot/storage"
+
+// Добавить алерт
+func (r *Repository) AddPriceAlert(a storage.PriceAlert) (int64, error) {
+	var id int64
+	err := r.db.QueryRow(`
+		INSERT INTO price_alerts (user_id, ticker, op, level)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, a.UserID, a.Ticker, a.Op, a.Level).Scan(&id)
+	return id, err
+}
+
+// Удалить алерт пользователя
+func (r *Repository) DeletePriceAlert(userID, id int64) error {
+	_, err := r.db.Exec(`DELETE FROM price_alerts WHERE id=$1 AND user_id=$2`, id, userID)
+	return err
+}
+
+// Получить активные алерты пользователя
+func (r *Repository) GetUserPriceAlerts(userID int64) ([]storage.PriceAlert, error) {
+	return r.queryPriceAlerts(`
+		SELECT id, user_id, ticker, op, level, created_at
+		FROM price_alerts
+		WHERE user_id=$1 AND triggered_at IS NULL
+		ORDER BY ticker, level
+	`, userID)
+}
+
+// Получить все несработавшие алерты
+func (r *Repository) GetActivePriceAlerts() ([]storage.PriceAlert, error) {
+	return r.queryPriceAlerts(`
+		SELECT id, user_id, ticker, op, level, created_at
+		FROM price_alerts
+		WHERE triggered_at IS NULL
+	`)
+}
+
+// Отметить алерт сработавшим. Возвращает false, если он уже сработал,
+// чтобы уведомление ушло только один раз
+func (r *Repository) MarkPriceAlertTriggered(id int64) (bool, error) {
+	res, err := r.db.Exec(`
+		UPDATE price_alerts SET triggered_at = NOW()
+		WHERE id=$1 AND triggered_at IS NULL
+	`, id)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+func (r *Repository) queryPriceAlerts(query string, args ...interface{}) ([]storage.PriceAlert, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []storage.PriceAlert
+	for rows.Next() {
+		var a storage.PriceAlert
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Ticker, &a.Op, &a.Level, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, nil
+}
@@ -0,0 +1,215 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/calendar"
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+// Добавить ICS-календарь
+func (r *Repository) AddCalendarSource(url string) error {
+	_, err := r.db.Exec(`INSERT INTO calendar_sources (url) VALUES ($1) ON CONFLICT DO NOTHING`, url)
+	return err
+}
+
+// Удалить ICS-календарь вместе с его событиями
+func (r *Repository) RemoveCalendarSource(url string) error {
+	if _, err := r.db.Exec(`DELETE FROM events WHERE source=$1`, url); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(`DELETE FROM calendar_sources WHERE url=$1`, url)
+	return err
+}
+
+// Получить все ICS-календари
+func (r *Repository) GetCalendarSources() ([]string, error) {
+	rows, err := r.db.Query(`SELECT url FROM calendar_sources ORDER BY url`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var urls []string
+	for rows.Next() {
+		var u string
+		if err := rows.Scan(&u); err != nil {
+			return nil, err
+		}
+		urls = append(urls, u)
+	}
+	return urls, nil
+}
+
+// Сохранить события календаря (обновляет уже известные по UID)
+func (r *Repository) SaveEvents(source string, events []calendar.Event) error {
+	for _, e := range events {
+		_, err := r.db.Exec(`
+			INSERT INTO events (uid, title, description, category, starts_at, all_day, source)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (uid) DO UPDATE SET
+				title = EXCLUDED.title,
+				description = EXCLUDED.description,
+				category = EXCLUDED.category,
+				starts_at = EXCLUDED.starts_at,
+				all_day = EXCLUDED.all_day,
+				source = EXCLUDED.source
+		`, e.UID, e.Title, e.Description, e.Category, e.Start, e.AllDay, source)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Получить события в интервале [from, to)
+func (r *Repository) GetEventsBetween(from, to time.Time) ([]calendar.Event, error) {
+	rows, err := r.db.Query(`
+		SELECT uid, title, COALESCE(description, ''), category, starts_at, all_day
+		FROM events
+		WHERE starts_at >= $1 AND starts_at < $2
+		ORDER BY starts_at
+	`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []calendar.Event
+	for rows.Next() {
+		var e calendar.Event
+		if err := rows.Scan(&e.UID, &e.Title, &e.Description, &e.Category, &e.Start, &e.AllDay); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// Получить все категории событий
+func (r *Repository) GetEventCategories() ([]string, error) {
+	rows, err := r.db.Query(`SELECT DISTINCT category FROM events ORDER BY category`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cats []string
+	for rows.Next() {
+		var c string
+		if err := rows.Scan(&c); err != nil {
+			return nil, err
+		}
+		cats = append(cats, c)
+	}
+	return cats, nil
+}
+
+// Подписать пользователя на категорию событий
+func (r *Repository) SubscribeCalendar(userID int64, category string, remindBefore int) error {
+	_, err := r.db.Exec(`
+		INSERT INTO calendar_subscriptions (user_id, category, remind_before)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, category) DO UPDATE SET remind_before = EXCLUDED.remind_before
+	`, userID, category, remindBefore)
+	return err
+}
+
+// Отписать пользователя от категории событий
+func (r *Repository) UnsubscribeCalendar(userID int64, category string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM calendar_subscriptions WHERE user_id=$1 AND category=$2`, userID, category)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+// Получить подписки пользователя на календарь (категория → минут до события)
+func (r *Repository) GetCalendarSubscriptions(userID int64) (map[string]int, error) {
+	rows, err := r.db.Query(`SELECT category, remind_before FROM calendar_subscriptions WHERE user_id=$1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs := make(map[string]int)
+	for rows.Next() {
+		var c string
+		var m int
+		if err := rows.Scan(&c, &m); err != nil {
+			return nil, err
+		}
+		subs[c] = m
+	}
+	return subs, nil
+}
+
+// Получить напоминания, которые пора отправить
+func (r *Repository) GetDueEventReminders() ([]storage.EventNotification, error) {
+	return r.queryEventNotifications(`
+		SELECT DISTINCT ON (s.user_id, e.uid)
+			s.user_id, e.uid, e.title, COALESCE(e.description, ''), e.category, e.starts_at, e.all_day, s.remind_before
+		FROM calendar_subscriptions s
+		JOIN events e ON e.category = s.category OR s.category = $1
+		WHERE NOT e.all_day
+		AND e.starts_at > NOW()
+		AND e.starts_at - make_interval(mins => s.remind_before) <= NOW()
+		AND NOT EXISTS (
+			SELECT 1 FROM event_notifications x
+			WHERE x.user_id = s.user_id AND x.event_uid = e.uid AND x.kind = $2
+		)
+		ORDER BY s.user_id, e.uid, s.remind_before DESC
+	`, storage.AllCategories, storage.EventNotifyReminder)
+}
+
+// Получить прошедшие события, по которым пора отправить подборку новостей.
+// after — сколько времени должно пройти после начала события
+func (r *Repository) GetDueEventFollowups(after time.Duration) ([]storage.EventNotification, error) {
+	return r.queryEventNotifications(`
+		SELECT DISTINCT ON (s.user_id, e.uid)
+			s.user_id, e.uid, e.title, COALESCE(e.description, ''), e.category, e.starts_at, e.all_day, s.remind_before
+		FROM calendar_subscriptions s
+		JOIN events e ON e.category = s.category OR s.category = $1
+		WHERE NOT e.all_day
+		AND e.starts_at <= NOW() - make_interval(secs => $3)
+		AND e.starts_at > NOW() - INTERVAL '1 day'
+		AND NOT EXISTS (
+			SELECT 1 FROM event_notifications x
+			WHERE x.user_id = s.user_id AND x.event_uid = e.uid AND x.kind = $2
+		)
+		ORDER BY s.user_id, e.uid
+	`, storage.AllCategories, storage.EventNotifyNews, after.Seconds())
+}
+
+// Отметить уведомление отправленным. Возвращает false, если оно уже было
+func (r *Repository) MarkEventNotified(userID int64, uid, kind string) (bool, error) {
+	res, err := r.db.Exec(`
+		INSERT INTO event_notifications (user_id, event_uid, kind)
+		VALUES ($1, $2, $3) ON CONFLICT DO NOTHING
+	`, userID, uid, kind)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+func (r *Repository) queryEventNotifications(query string, args ...interface{}) ([]storage.EventNotification, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []storage.EventNotification
+	for rows.Next() {
+		var n storage.EventNotification
+		e := &n.Event
+		if err := rows.Scan(&n.UserID, &e.UID, &e.Title, &e.Description, &e.Category, &e.Start, &e.AllDay, &n.RemindBefore); err != nil {
+			return nil, err
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
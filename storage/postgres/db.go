@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// Максимальная пауза между попытками подключения
+const maxConnectBackoff = 30 * time.Second
+
+// DBConfig — параметры подключения к PostgreSQL
+type DBConfig struct {
+	// URL — полная строка подключения (DATABASE_URL); если задана,
+	// отдельные параметры ниже игнорируются
+	URL string
+
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Name     string
+	SSLMode  string
+
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+
+	// ConnectRetries — сколько раз пробовать подключиться при старте,
+	// ConnectBackoff — первая пауза между попытками (дальше удваивается)
+	ConnectRetries int
+	ConnectBackoff time.Duration
+}
+
+// DBConfigFromEnv читает настройки из окружения:
+// DATABASE_URL или DB_HOST, DB_PORT, DB_USER, DB_PASSWORD, DB_NAME, DB_SSLMODE;
+// пул — DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS, DB_CONN_MAX_LIFETIME, DB_CONN_MAX_IDLE_TIME;
+// старт — DB_CONNECT_RETRIES, DB_CONNECT_BACKOFF
+func DBConfigFromEnv() (DBConfig, error) {
+	cfg := DBConfig{
+		URL:      os.Getenv("DATABASE_URL"),
+		Host:     envString("DB_HOST", "/var/run/postgresql"),
+		User:     envString("DB_USER", "postgres"),
+		Password: envString("DB_PASSWORD", "postgres"),
+		Name:     envString("DB_NAME", "news_feed_bot"),
+		SSLMode:  envString("DB_SSLMODE", "disable"),
+	}
+
+	var err error
+	if cfg.Port, err = envInt("DB_PORT", 5433); err != nil {
+		return cfg, err
+	}
+	if cfg.MaxOpenConns, err = envInt("DB_MAX_OPEN_CONNS", 10); err != nil {
+		return cfg, err
+	}
+	if cfg.MaxIdleConns, err = envInt("DB_MAX_IDLE_CONNS", 5); err != nil {
+		return cfg, err
+	}
+	if cfg.ConnMaxLifetime, err = envDuration("DB_CONN_MAX_LIFETIME", 30*time.Minute); err != nil {
+		return cfg, err
+	}
+	if cfg.ConnMaxIdleTime, err = envDuration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute); err != nil {
+		return cfg, err
+	}
+	if cfg.ConnectRetries, err = envInt("DB_CONNECT_RETRIES", 10); err != nil {
+		return cfg, err
+	}
+	if cfg.ConnectBackoff, err = envDuration("DB_CONNECT_BACKOFF", time.Second); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// DSN возвращает строку подключения для lib/pq
+func (c DBConfig) DSN() string {
+	if c.URL != "" {
+		return c.URL
+	}
+	parts := []string{
+		"host=" + quoteDSN(c.Host),
+		"port=" + strconv.Itoa(c.Port),
+		"user=" + quoteDSN(c.User),
+		"dbname=" + quoteDSN(c.Name),
+		"sslmode=" + quoteDSN(c.SSLMode),
+	}
+	if c.Password != "" {
+		parts = append(parts, "password="+quoteDSN(c.Password))
+	}
+	return strings.Join(parts, " ")
+}
+
+var dsnPasswordRe = regexp.MustCompile(`password=('(?:[^'\\]|\\.)*'|\S+)`)
+
+// RedactedDSN — строка подключения без пароля, для логов
+func (c DBConfig) RedactedDSN() string {
+	dsn := c.DSN()
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		if u, err := url.Parse(dsn); err == nil {
+			return u.Redacted()
+		}
+		return "postgres://<не удалось разобрать URL>"
+	}
+	return dsnPasswordRe.ReplaceAllString(dsn, "password=xxxxx")
+}
+
+// Подключение к PostgreSQL с настройкой пула и повторными попытками при старте
+func ConnectDB(cfg DBConfig) (*sql.DB, error) {
+	log.Printf("Подключение к БД: %s", cfg.RedactedDSN())
+
+	db, err := sql.Open("postgres", cfg.DSN())
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	backoff := cfg.ConnectBackoff
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err = db.PingContext(ctx)
+		cancel()
+		if err == nil {
+			return db, nil
+		}
+		if attempt >= cfg.ConnectRetries {
+			db.Close()
+			return nil, fmt.Errorf("БД недоступна после %d попыток: %w", attempt, err)
+		}
+
+		log.Printf("БД недоступна (попытка %d/%d): %v, повтор через %s", attempt, cfg.ConnectRetries, err, backoff)
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > maxConnectBackoff {
+			backoff = maxConnectBackoff
+		}
+	}
+}
+
+// quoteDSN экранирует значение для формата key=value
+func quoteDSN(v string) string {
+	if v != "" && !strings.ContainsAny(v, ` '\`) {
+		return v
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+}
+
+func envString(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func envInt(key string, def int) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+	return n, nil
+}
+
+func envDuration(key string, def time.Duration) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+	return d, nil
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage/schema"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// Ключ advisory lock, чтобы миграции не запускались параллельно
+const migrationLockKey = 7346512001
+
+// Schema — миграции PostgreSQL
+var Schema = schema.Dialect{
+	FS: migrationsFS,
+	CreateTable: `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`,
+	Lock: advisoryLock,
+}
+
+// advisoryLock берёт session-level lock, который держится на conn
+func advisoryLock(ctx context.Context, conn *sql.Conn) (func(), error) {
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return nil, err
+	}
+	return func() {
+		conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+	}, nil
+}
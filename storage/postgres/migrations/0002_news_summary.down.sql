@@ -0,0 +1,2 @@
+ALTER TABLE news DROP COLUMN IF EXISTS summary;
+ALTER TABLE news DROP COLUMN IF EXISTS description;
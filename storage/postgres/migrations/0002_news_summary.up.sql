@@ -0,0 +1,2 @@
+ALTER TABLE news ADD COLUMN IF NOT EXISTS description TEXT;
+ALTER TABLE news ADD COLUMN IF NOT EXISTS summary TEXT;
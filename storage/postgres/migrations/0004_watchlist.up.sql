@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS watchlist (
+	user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+	ticker TEXT NOT NULL,
+	PRIMARY KEY (user_id, ticker)
+);
+CREATE INDEX IF NOT EXISTS watchlist_ticker_idx ON watchlist (ticker);
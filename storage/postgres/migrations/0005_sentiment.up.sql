@@ -0,0 +1,5 @@
+ALTER TABLE news ADD COLUMN IF NOT EXISTS sentiment REAL NOT NULL DEFAULT 0;
+CREATE TABLE IF NOT EXISTS user_prefs (
+	user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+	sentiment_filter TEXT NOT NULL DEFAULT 'all'
+);
@@ -0,0 +1 @@
+ALTER TABLE user_prefs DROP COLUMN IF EXISTS show_price;
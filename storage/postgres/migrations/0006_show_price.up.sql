@@ -0,0 +1 @@
+ALTER TABLE user_prefs ADD COLUMN IF NOT EXISTS show_price BOOLEAN NOT NULL DEFAULT FALSE;
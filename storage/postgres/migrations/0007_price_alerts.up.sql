@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS price_alerts (
+	id SERIAL PRIMARY KEY,
+	user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+	ticker TEXT NOT NULL,
+	op TEXT NOT NULL,
+	level DOUBLE PRECISION NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	triggered_at TIMESTAMP
+);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS event_notifications;
+DROP TABLE IF EXISTS calendar_subscriptions;
+DROP TABLE IF EXISTS events;
+DROP TABLE IF EXISTS calendar_sources;
+ALTER TABLE user_prefs DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,26 @@
+ALTER TABLE user_prefs ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'Europe/Moscow';
+CREATE TABLE IF NOT EXISTS calendar_sources (
+	url TEXT PRIMARY KEY
+);
+CREATE TABLE IF NOT EXISTS events (
+	uid TEXT PRIMARY KEY,
+	title TEXT NOT NULL,
+	description TEXT,
+	category TEXT NOT NULL,
+	starts_at TIMESTAMPTZ NOT NULL,
+	all_day BOOLEAN NOT NULL DEFAULT FALSE,
+	source TEXT
+);
+CREATE INDEX IF NOT EXISTS events_starts_at_idx ON events (starts_at);
+CREATE TABLE IF NOT EXISTS calendar_subscriptions (
+	user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+	category TEXT NOT NULL,
+	remind_before INT NOT NULL DEFAULT 15,
+	PRIMARY KEY (user_id, category)
+);
+CREATE TABLE IF NOT EXISTS event_notifications (
+	user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+	event_uid TEXT REFERENCES events(uid) ON DELETE CASCADE,
+	kind TEXT NOT NULL,
+	PRIMARY KEY (user_id, event_uid, kind)
+);
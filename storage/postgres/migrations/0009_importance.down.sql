@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS deferred_news;
+DROP TABLE IF EXISTS importance_rules;
+ALTER TABLE user_prefs DROP COLUMN IF EXISTS importance_threshold;
+ALTER TABLE sources DROP COLUMN IF EXISTS priority;
+ALTER TABLE news DROP COLUMN IF EXISTS importance;
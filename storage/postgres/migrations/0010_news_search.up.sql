@@ -0,0 +1,7 @@
+ALTER TABLE news ADD COLUMN IF NOT EXISTS search tsvector GENERATED ALWAYS AS (
+	setweight(to_tsvector('russian', COALESCE(title, '')), 'A') ||
+	setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+	setweight(to_tsvector('russian', COALESCE(description, '')), 'B') ||
+	setweight(to_tsvector('english', COALESCE(description, '')), 'B')
+) STORED;
+CREATE INDEX IF NOT EXISTS news_search_idx ON news USING GIN (search);
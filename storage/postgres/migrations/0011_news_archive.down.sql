@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS news_pub_date_idx;
+DROP TABLE IF EXISTS news_archive;
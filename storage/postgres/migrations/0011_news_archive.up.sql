@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS news_archive (
+	link TEXT PRIMARY KEY,
+	title TEXT,
+	pub_date TIMESTAMP,
+	source_url TEXT,
+	description TEXT,
+	summary TEXT,
+	sentiment REAL NOT NULL DEFAULT 0,
+	importance INT NOT NULL DEFAULT 0,
+	tags TEXT NOT NULL DEFAULT '',
+	archived_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS news_archive_pub_date_idx ON news_archive (pub_date);
+CREATE INDEX IF NOT EXISTS news_pub_date_idx ON news (pub_date);
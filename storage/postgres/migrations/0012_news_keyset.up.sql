@@ -0,0 +1,5 @@
+ALTER TABLE news ADD COLUMN IF NOT EXISTS id BIGSERIAL;
+CREATE UNIQUE INDEX IF NOT EXISTS news_id_idx ON news (id);
+DROP INDEX IF EXISTS news_pub_date_idx;
+CREATE INDEX IF NOT EXISTS news_pub_date_id_idx ON news (pub_date DESC, id DESC);
+CREATE INDEX IF NOT EXISTS news_source_pub_date_id_idx ON news (source_url, pub_date DESC, id DESC);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS user_autopost (
+	user_id BIGINT PRIMARY KEY,
+	times TEXT
+);
+
+-- дни недели, пояс и выключенные записи в старом формате не выражаются
+INSERT INTO user_autopost (user_id, times)
+SELECT user_id, json_agg(lpad((time_of_day / 60)::text, 2, '0') || ':' || lpad((time_of_day % 60)::text, 2, '0') ORDER BY time_of_day)::text
+FROM autopost_schedules
+WHERE enabled
+GROUP BY user_id;
+
+DROP TABLE autopost_schedules;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS autopost_schedules (
+	user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	time_of_day SMALLINT NOT NULL CHECK (time_of_day >= 0 AND time_of_day < 1440),
+	weekdays SMALLINT NOT NULL DEFAULT 127 CHECK (weekdays > 0 AND weekdays < 128),
+	timezone TEXT NOT NULL DEFAULT 'Europe/Moscow',
+	enabled BOOLEAN NOT NULL DEFAULT TRUE,
+	PRIMARY KEY (user_id, time_of_day)
+);
+
+-- раньше время хранилось JSON-массивом строк HH:MM по Москве; некорректные значения отбрасываются
+INSERT INTO autopost_schedules (user_id, time_of_day)
+SELECT a.user_id, split_part(t, ':', 1)::int * 60 + split_part(t, ':', 2)::int
+FROM user_autopost a
+CROSS JOIN LATERAL json_array_elements_text(a.times::json) t
+WHERE a.times LIKE '[%'
+AND t ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$'
+AND a.user_id IN (SELECT id FROM users)
+ON CONFLICT DO NOTHING;
+
+DROP TABLE user_autopost;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS settings_history (
+	id BIGSERIAL PRIMARY KEY,
+	key TEXT NOT NULL,
+	old_value TEXT,
+	new_value TEXT NOT NULL,
+	admin_id BIGINT NOT NULL,
+	changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS settings_history_key_idx ON settings_history (key, changed_at DESC, id DESC);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS users_last_seen_idx;
+DROP INDEX IF EXISTS users_username_idx;
+ALTER TABLE users DROP COLUMN IF EXISTS status;
+ALTER TABLE users DROP COLUMN IF EXISTS last_seen_at;
+ALTER TABLE users DROP COLUMN IF EXISTS joined_at;
+ALTER TABLE users DROP COLUMN IF EXISTS language_code;
+ALTER TABLE users DROP COLUMN IF EXISTS last_name;
+ALTER TABLE users DROP COLUMN IF EXISTS first_name;
+ALTER TABLE users DROP COLUMN IF EXISTS username;
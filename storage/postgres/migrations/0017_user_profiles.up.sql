@@ -0,0 +1,12 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS username TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS first_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS language_code TEXT NOT NULL DEFAULT '';
+-- дата прихода тех, кто был до профилей, неизвестна: берётся первый ценовой алерт, иначе NULL
+ALTER TABLE users ADD COLUMN IF NOT EXISTS joined_at TIMESTAMPTZ;
+UPDATE users u SET joined_at = (SELECT MIN(a.created_at)::timestamptz FROM price_alerts a WHERE a.user_id = u.id);
+ALTER TABLE users ALTER COLUMN joined_at SET DEFAULT NOW();
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active';
+CREATE INDEX IF NOT EXISTS users_username_idx ON users (lower(username));
+CREATE INDEX IF NOT EXISTS users_last_seen_idx ON users (last_seen_at);
@@ -0,0 +1,7 @@
+ALTER TABLE sources DROP COLUMN IF EXISTS sort_order;
+ALTER TABLE sources DROP COLUMN IF EXISTS added_at;
+ALTER TABLE sources DROP COLUMN IF EXISTS added_by;
+ALTER TABLE sources DROP COLUMN IF EXISTS language;
+ALTER TABLE sources DROP COLUMN IF EXISTS category;
+ALTER TABLE sources DROP COLUMN IF EXISTS description;
+ALTER TABLE sources DROP COLUMN IF EXISTS title;
@@ -0,0 +1,7 @@
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS title TEXT NOT NULL DEFAULT '';
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '';
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS category TEXT NOT NULL DEFAULT '';
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS language TEXT NOT NULL DEFAULT '';
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS added_by BIGINT;
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS added_at TIMESTAMPTZ;
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS sort_order INT NOT NULL DEFAULT 0;
@@ -0,0 +1,252 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/sentiment"
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+	"github.com/lib/pq"
+)
+
+// Теги новости одной строкой; запросы выбирают из news с алиасом n
+const tagsColumn = `COALESCE((SELECT string_agg(ticker, ',' ORDER BY ticker) FROM news_tags WHERE news_link = n.link), '')`
+
+// Колонки для scanNews
+const newsColumns = `n.id, n.title, n.link, n.pub_date, n.source_url, COALESCE(n.summary, ''), n.sentiment, n.importance, ` + tagsColumn
+
+// Условие фильтра тональности пользователя; $1 — ID пользователя
+var sentimentCondition = fmt.Sprintf(`
+	CASE COALESCE((SELECT sentiment_filter FROM user_prefs WHERE user_id = $1), '%[1]s')
+		WHEN '%[2]s' THEN n.sentiment >= %[5]g
+		WHEN '%[3]s' THEN n.sentiment <= -%[5]g
+		WHEN '%[4]s' THEN abs(n.sentiment) >= %[5]g
+		ELSE TRUE
+	END`, sentiment.FilterAll, sentiment.FilterPositive, sentiment.FilterNegative, sentiment.FilterStrong, sentiment.Strong)
+
+func scanNews(rows *sql.Rows) ([]storage.NewsItem, error) {
+	var items []storage.NewsItem
+	for rows.Next() {
+		n, err := scanNewsRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, n)
+	}
+	return items, nil
+}
+
+// scanNewsRow читает newsColumns; extra — колонки, выбранные перед ними
+func scanNewsRow(rows *sql.Rows, extra ...interface{}) (storage.NewsItem, error) {
+	var n storage.NewsItem
+	var tags string
+	dest := append(extra, &n.ID, &n.Title, &n.Link, &n.PubDate, &n.Source, &n.Summary, &n.Sentiment, &n.Importance, &tags)
+	if err := rows.Scan(dest...); err != nil {
+		return n, err
+	}
+	if tags != "" {
+		n.Tags = strings.Split(tags, ",")
+	}
+	return n, nil
+}
+
+// SaveNews сохраняет новость вместе с тегами и в той же транзакции ставит её
+// в outbox получателям. inserted == false, если такая новость уже была в базе
+func (r *Repository) SaveNews(n storage.NewsItem, description string) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// pub_date — TIMESTAMP без пояса, и сравнивается он с UTC: пояс фида
+	// переводится здесь, иначе смещение фида молча теряется
+	res, err := tx.Exec(`
+		INSERT INTO news (link, title, pub_date, source_url, description, summary, sentiment, importance)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8) ON CONFLICT DO NOTHING
+	`, n.Link, n.Title, n.PubDate.UTC().Truncate(time.Microsecond), n.Source, description, n.Summary, n.Sentiment, n.Importance)
+	if err != nil {
+		return false, err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return false, nil
+	}
+	if err := saveNewsTags(tx, n.Link, n.Tags); err != nil {
+		return false, err
+	}
+
+	// получатели — подписчики источника и те, у кого тикер новости в watchlist
+	if _, err := tx.Exec(`
+		INSERT INTO outbox (user_id, news_link, next_attempt_at, created_at)
+		SELECT recipients.user_id, $1::text, $3::timestamptz, $3::timestamptz FROM (
+			SELECT user_id FROM subscriptions WHERE source_url = $4
+			UNION
+			SELECT user_id FROM watchlist WHERE ticker = ANY($2)
+		) recipients
+		JOIN users u ON u.id = recipients.user_id AND u.status = 'active'
+		ON CONFLICT DO NOTHING
+	`, n.Link, pq.Array(n.Tags), time.Now().UTC(), n.Source); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// Какие из ссылок уже есть в базе
+func (r *Repository) KnownLinks(links []string) (map[string]bool, error) {
+	rows, err := r.db.Query(`SELECT link FROM news WHERE link = ANY($1)`, pq.Array(links))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanLinks(rows)
+}
+
+// scanLinks собирает выбранные ссылки в множество
+func scanLinks(rows *sql.Rows) (map[string]bool, error) {
+	known := make(map[string]bool)
+	for rows.Next() {
+		var link string
+		if err := rows.Scan(&link); err != nil {
+			return nil, err
+		}
+		known[link] = true
+	}
+	return known, rows.Err()
+}
+
+// GetCoverage — сколько других источников недавно писали о тех же тикерах
+func (r *Repository) GetCoverage(src string, tags []string) (int, error) {
+	if len(tags) == 0 {
+		return 0, nil
+	}
+	var count int
+	err := r.db.QueryRow(`
+		SELECT COUNT(DISTINCT n.source_url)
+		FROM news n
+		JOIN news_tags t ON t.news_link = n.link
+		WHERE t.ticker = ANY($1)
+		AND n.source_url <> $2
+		AND n.pub_date > $3
+	`, pq.Array(tags), src, time.Now().UTC().Add(-storage.CoverageWindow)).Scan(&count)
+	return count, err
+}
+
+// saveNewsTags сохраняет теги новости
+func saveNewsTags(tx *sql.Tx, link string, tags []string) error {
+	for _, t := range tags {
+		if _, err := tx.Exec(`
+			INSERT INTO news_tags (news_link, ticker)
+			VALUES ($1, $2) ON CONFLICT DO NOTHING
+		`, link, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Количество новостей из подписок пользователя.
+// Считается по всей таблице, поэтому бот кэширует результат
+func (r *Repository) CountLatestNewsForUser(userID int64) (int, error) {
+	var count int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM news n
+		WHERE n.source_url IN (SELECT source_url FROM subscriptions WHERE user_id = $1)
+		AND `+sentimentCondition+`
+	`, userID).Scan(&count)
+	return count, err
+}
+
+// Получить страницу последних новостей пользователя относительно курсора.
+// Keyset по (pub_date, id) не сдвигает страницы при появлении новых новостей
+func (r *Repository) GetLatestNewsForUser(userID int64, cursor storage.NewsCursor, dir storage.PageDirection, limit int) ([]storage.NewsItem, error) {
+	cond, order, reverse := keyset(dir)
+	args := []interface{}{userID, limit}
+	if cond != "" {
+		args = append(args, cursor.PubDate.UTC(), cursor.ID)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT `+newsColumns+`
+		FROM news n
+		WHERE n.source_url IN (SELECT source_url FROM subscriptions WHERE user_id = $1)
+		AND `+sentimentCondition+cond+`
+		ORDER BY `+order+`
+		LIMIT $2
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items, err := scanNews(rows)
+	if reverse {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	return items, err
+}
+
+// keyset возвращает условие на курсор ($3, $4), порядок выборки и нужно ли
+// развернуть результат, чтобы страница шла от новых к старым
+func keyset(dir storage.PageDirection) (cond, order string, reverse bool) {
+	const desc, asc = "n.pub_date DESC, n.id DESC", "n.pub_date, n.id"
+	switch dir {
+	case storage.PageAt:
+		return "\n\t\tAND (n.pub_date, n.id) <= ($3, $4)", desc, false
+	case storage.PageAfter:
+		return "\n\t\tAND (n.pub_date, n.id) < ($3, $4)", desc, false
+	case storage.PageBefore:
+		return "\n\t\tAND (n.pub_date, n.id) > ($3, $4)", asc, true
+	case storage.PageLast:
+		return "", asc, true
+	}
+	return "", desc, false
+}
+
+// Получить новости из подписок пользователя, вышедшие в интервале [from, to]
+func (r *Repository) GetNewsBetweenForUser(userID int64, from, to time.Time, limit int) ([]storage.NewsItem, error) {
+	rows, err := r.db.Query(`
+		SELECT `+newsColumns+`
+		FROM news n
+		WHERE n.source_url IN (SELECT source_url FROM subscriptions WHERE user_id = $1)
+		AND n.pub_date BETWEEN $2 AND $3
+		ORDER BY n.pub_date
+		LIMIT $4
+	`, userID, from.UTC(), to.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanNews(rows)
+}
+
+// Отложить новость до следующего дайджеста
+func (r *Repository) DeferNews(userID int64, link string) error {
+	_, err := r.db.Exec(`
+		INSERT INTO deferred_news (user_id, news_link)
+		VALUES ($1, $2) ON CONFLICT DO NOTHING
+	`, userID, link)
+	return err
+}
+
+// Забрать отложенные новости пользователя (они удаляются из очереди)
+func (r *Repository) PopDeferredNews(userID int64) ([]storage.NewsItem, error) {
+	rows, err := r.db.Query(`
+		WITH d AS (
+			DELETE FROM deferred_news WHERE user_id = $1 RETURNING news_link
+		)
+		SELECT `+newsColumns+`
+		FROM news n
+		JOIN d ON d.news_link = n.link
+		ORDER BY n.importance DESC, n.pub_date DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanNews(rows)
+}
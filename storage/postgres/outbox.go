@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+// Забрать доставки, которым пора уйти, и зарезервировать их на lease.
+// SKIP LOCKED позволяет нескольким отправителям не мешать друг другу
+func (r *Repository) ClaimDeliveries(limit int, lease time.Duration) ([]storage.Delivery, error) {
+	now := time.Now().UTC()
+	rows, err := r.db.Query(`
+		WITH claimed AS (
+			UPDATE outbox o
+			SET claimed_until = $2
+			FROM (
+				SELECT id FROM outbox
+				WHERE status = 'pending' AND next_attempt_at <= $1
+				AND (claimed_until IS NULL OR claimed_until < $1)
+				ORDER BY next_attempt_at, id
+				LIMIT $3
+				FOR UPDATE SKIP LOCKED
+			) due
+			WHERE o.id = due.id
+			RETURNING o.id, o.user_id, o.news_link, o.attempts
+		)
+		SELECT c.id, c.user_id, c.attempts,
+			EXISTS (SELECT 1 FROM user_read_news u WHERE u.user_id = c.user_id AND u.news_id = c.news_link),
+			`+newsColumns+`
+		FROM claimed c
+		JOIN news n ON n.link = c.news_link
+		ORDER BY c.id
+	`, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []storage.Delivery
+	for rows.Next() {
+		var d storage.Delivery
+		if d.News, err = scanNewsRow(rows, &d.ID, &d.UserID, &d.Attempts, &d.Read); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// Завершить доставку
+func (r *Repository) CompleteDelivery(id int64, status, lastError string) error {
+	_, err := r.db.Exec(`
+		UPDATE outbox
+		SET status = $2, last_error = NULLIF($3, ''), claimed_until = NULL, done_at = $4
+		WHERE id = $1
+	`, id, status, lastError, time.Now().UTC())
+	return err
+}
+
+// Вернуть доставку в очередь после ошибки
+func (r *Repository) RetryDelivery(id int64, next time.Time, lastError string) error {
+	_, err := r.db.Exec(`
+		UPDATE outbox
+		SET attempts = attempts + 1, next_attempt_at = $2, last_error = $3, claimed_until = NULL
+		WHERE id = $1
+	`, id, next.UTC(), lastError)
+	return err
+}
+
+// Количество доставок, ожидающих отправки
+func (r *Repository) CountPendingDeliveries() (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM outbox WHERE status = 'pending'`).Scan(&count)
+	return count, err
+}
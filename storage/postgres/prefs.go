@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/sentiment"
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+// Установить фильтр тональности пользователя
+func (r *Repository) SetSentimentFilter(userID int64, filter string) error {
+	_, err := r.db.Exec(`
+		INSERT INTO user_prefs (user_id, sentiment_filter)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET sentiment_filter = EXCLUDED.sentiment_filter
+	`, userID, filter)
+	return err
+}
+
+// Получить фильтр тональности пользователя
+func (r *Repository) GetSentimentFilter(userID int64) (string, error) {
+	var filter string
+	err := r.db.QueryRow(`SELECT sentiment_filter FROM user_prefs WHERE user_id=$1`, userID).Scan(&filter)
+	if err == sql.ErrNoRows {
+		return sentiment.FilterAll, nil
+	}
+	return filter, err
+}
+
+// Получить фильтры всех пользователей, у которых он задан
+func (r *Repository) GetSentimentFilters() (map[int64]string, error) {
+	rows, err := r.db.Query(`SELECT user_id, sentiment_filter FROM user_prefs WHERE sentiment_filter <> $1`, sentiment.FilterAll)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	filters := make(map[int64]string)
+	for rows.Next() {
+		var uid int64
+		var f string
+		if err := rows.Scan(&uid, &f); err != nil {
+			return nil, err
+		}
+		filters[uid] = f
+	}
+	return filters, nil
+}
+
+// Включить или выключить строку с ценой у новостей
+func (r *Repository) SetShowPrice(userID int64, show bool) error {
+	_, err := r.db.Exec(`
+		INSERT INTO user_prefs (user_id, show_price)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET show_price = EXCLUDED.show_price
+	`, userID, show)
+	return err
+}
+
+// Показывать ли пользователю цену у новостей
+func (r *Repository) GetShowPrice(userID int64) (bool, error) {
+	var show bool
+	err := r.db.QueryRow(`SELECT show_price FROM user_prefs WHERE user_id=$1`, userID).Scan(&show)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return show, err
+}
+
+// Получить пользователей, включивших строку с ценой
+func (r *Repository) GetShowPriceUsers() (map[int64]bool, error) {
+	rows, err := r.db.Query(`SELECT user_id FROM user_prefs WHERE show_price`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make(map[int64]bool)
+	for rows.Next() {
+		var uid int64
+		if err := rows.Scan(&uid); err != nil {
+			return nil, err
+		}
+		users[uid] = true
+	}
+	return users, nil
+}
+
+// Установить часовой пояс пользователя (IANA, например Europe/Moscow)
+func (r *Repository) SetTimezone(userID int64, tz string) error {
+	_, err := r.db.Exec(`
+		INSERT INTO user_prefs (user_id, timezone)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET timezone = EXCLUDED.timezone
+	`, userID, tz)
+	return err
+}
+
+// Получить часовой пояс пользователя
+func (r *Repository) GetTimezone(userID int64) (string, error) {
+	var tz string
+	err := r.db.QueryRow(`SELECT timezone FROM user_prefs WHERE user_id=$1`, userID).Scan(&tz)
+	if err == sql.ErrNoRows {
+		return storage.DefaultTimezone, nil
+	}
+	return tz, err
+}
+
+// Установить порог важности для мгновенных уведомлений
+func (r *Repository) SetImportanceThreshold(userID int64, threshold int) error {
+	_, err := r.db.Exec(`
+		INSERT INTO user_prefs (user_id, importance_threshold)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET importance_threshold = EXCLUDED.importance_threshold
+	`, userID, threshold)
+	return err
+}
+
+// Получить порог важности пользователя
+func (r *Repository) GetImportanceThreshold(userID int64) (int, error) {
+	var threshold int
+	err := r.db.QueryRow(`SELECT importance_threshold FROM user_prefs WHERE user_id=$1`, userID).Scan(&threshold)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return threshold, err
+}
+
+// Получить пороги всех пользователей, у которых он задан
+func (r *Repository) GetImportanceThresholds() (map[int64]int, error) {
+	rows, err := r.db.Query(`SELECT user_id, importance_threshold FROM user_prefs WHERE importance_threshold <> 0`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	thresholds := make(map[int64]int)
+	for rows.Next() {
+		var uid int64
+		var t int
+		if err := rows.Scan(&uid, &t); err != nil {
+			return nil, err
+		}
+		thresholds[uid] = t
+	}
+	return thresholds, nil
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+	"github.com/lib/pq"
+)
+
+// Условие «новость не прочитана пользователем $1»
+const unreadCondition = `
+	NOT EXISTS (SELECT 1 FROM user_read_news u WHERE u.user_id = $1 AND u.news_id = n.link)`
+
+// Отметить новости прочитанными
+func (r *Repository) MarkNewsRead(userID int64, links []string) error {
+	if len(links) == 0 {
+		return nil
+	}
+	_, err := r.db.Exec(`
+		INSERT INTO user_read_news (user_id, news_id)
+		SELECT $1, link FROM news WHERE link = ANY($2)
+		ON CONFLICT DO NOTHING
+	`, userID, pq.Array(links))
+	return err
+}
+
+// Отметить прочитанными все новости из подписок пользователя
+func (r *Repository) MarkAllNewsRead(userID int64) (int, error) {
+	res, err := r.db.Exec(`
+		INSERT INTO user_read_news (user_id, news_id)
+		SELECT $1, n.link
+		FROM news n
+		WHERE n.source_url IN (SELECT source_url FROM subscriptions WHERE user_id = $1)
+		ON CONFLICT DO NOTHING
+	`, userID)
+	if err != nil {
+		return 0, err
+	}
+	affected, _ := res.RowsAffected()
+	return int(affected), nil
+}
+
+// Количество непрочитанных новостей из подписок пользователя
+func (r *Repository) CountUnreadNewsForUser(userID int64) (int, error) {
+	var count int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM news n
+		WHERE n.source_url IN (SELECT source_url FROM subscriptions WHERE user_id = $1)
+		AND `+sentimentCondition+`
+		AND `+unreadCondition+`
+	`, userID).Scan(&count)
+	return count, err
+}
+
+// Непрочитанные новости из подписок пользователя, от новых к старым
+func (r *Repository) GetUnreadNewsForUser(userID int64, limit int) ([]storage.NewsItem, error) {
+	rows, err := r.db.Query(`
+		SELECT `+newsColumns+`
+		FROM news n
+		WHERE n.source_url IN (SELECT source_url FROM subscriptions WHERE user_id = $1)
+		AND `+sentimentCondition+`
+		AND `+unreadCondition+`
+		ORDER BY n.pub_date DESC, n.id DESC
+		LIMIT $2
+	`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanNews(rows)
+}
+
+// Ссылки на прочитанные новости пользователя
+func (r *Repository) GetReadNews(userID int64) ([]string, error) {
+	rows, err := r.db.Query(`SELECT news_id FROM user_read_news WHERE user_id = $1 ORDER BY news_id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []string
+	for rows.Next() {
+		var link string
+		if err := rows.Scan(&link); err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+// Запрос сразу по русской и английской конфигурациям; $1 — текст запроса
+const tsQuery = `(websearch_to_tsquery('russian', $1) || websearch_to_tsquery('english', $1))`
+
+// Условие поиска: $2 — подстрока URL источника, $3 и $4 — интервал дат
+const searchCondition = `n.search @@ ` + tsQuery + `
+	AND ($2 = '' OR strpos(n.source_url, $2) > 0)
+	AND ($3::timestamp IS NULL OR n.pub_date >= $3)
+	AND ($4::timestamp IS NULL OR n.pub_date < $4)`
+
+// Найти новости по запросу, самые релевантные первыми
+func (r *Repository) SearchNews(q storage.SearchQuery, page, pageSize int) ([]storage.NewsItem, error) {
+	offset := (page - 1) * pageSize
+
+	rows, err := r.db.Query(`
+		SELECT `+newsColumns+`
+		FROM news n
+		WHERE `+searchCondition+`
+		ORDER BY ts_rank_cd(n.search, `+tsQuery+`) DESC, n.pub_date DESC
+		LIMIT $5 OFFSET $6
+	`, q.Text, q.Source, nullTime(q.From), nullTime(q.To), pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanNews(rows)
+}
+
+// Количество найденных новостей
+func (r *Repository) CountSearchNews(q storage.SearchQuery) (int, error) {
+	var count int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM news n
+		WHERE `+searchCondition+`
+	`, q.Text, q.Source, nullTime(q.From), nullTime(q.To)).Scan(&count)
+	return count, err
+}
+
+// nullTime — NULL для нулевого времени
+func nullTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
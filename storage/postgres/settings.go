@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+// Установить значение и записать изменение в историю
+func (r *Repository) SetSetting(key, value string, adminID int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var old sql.NullString
+	if err := tx.QueryRow(`SELECT value FROM settings WHERE key = $1`, key).Scan(&old); err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO settings (key, value)
+		VALUES ($1, $2)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value
+	`, key, value); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO settings_history (key, old_value, new_value, admin_id, changed_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, key, old, value, adminID, time.Now().UTC()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Получить значение
+func (r *Repository) GetSetting(key string) (string, error) {
+	var value string
+	err := r.db.QueryRow(`SELECT value FROM settings WHERE key=$1`, key).Scan(&value)
+	if err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// Получить все настройки
+func (r *Repository) GetAllSettings() (map[string]string, error) {
+	rows, err := r.db.Query(`SELECT key, value FROM settings`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	settings := make(map[string]string)
+	for rows.Next() {
+		var k, v string
+		if err := rows.Scan(&k, &v); err == nil {
+			settings[k] = v
+		}
+	}
+	return settings, nil
+}
+
+// История изменений настройки, от новых к старым
+func (r *Repository) GetSettingHistory(key string, limit int) ([]storage.SettingChange, error) {
+	rows, err := r.db.Query(`
+		SELECT key, COALESCE(old_value, ''), new_value, admin_id, changed_at
+		FROM settings_history
+		WHERE key = $1
+		ORDER BY changed_at DESC, id DESC
+		LIMIT $2
+	`, key, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []storage.SettingChange
+	for rows.Next() {
+		var c storage.SettingChange
+		if err := rows.Scan(&c.Key, &c.OldValue, &c.NewValue, &c.AdminID, &c.ChangedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, c)
+	}
+	return history, rows.Err()
+}
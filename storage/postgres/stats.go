@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+func (r *Repository) CountNewsByDay(since time.Time) ([]storage.DayCount, error) {
+	rows, err := r.db.Query(`
+		SELECT date_trunc('day', pub_date), source_url, COUNT(*)
+		FROM (
+			SELECT pub_date, source_url FROM news WHERE pub_date >= $1
+			UNION ALL
+			SELECT pub_date, source_url FROM news_archive WHERE pub_date >= $1
+		) n
+		GROUP BY 1, 2
+		ORDER BY 1, 2
+	`, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanDayCounts(rows)
+}
+
+func (r *Repository) CountPushesByDay(since time.Time) ([]storage.DayCount, error) {
+	rows, err := r.db.Query(`
+		SELECT date_trunc('day', done_at AT TIME ZONE 'UTC'), '', COUNT(*)
+		FROM outbox
+		WHERE status = $1 AND done_at >= $2
+		GROUP BY 1
+		ORDER BY 1
+	`, storage.DeliverySent, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanDayCounts(rows)
+}
+
+func (r *Repository) CountNewUsersByDay(since time.Time) ([]storage.DayCount, error) {
+	rows, err := r.db.Query(`
+		SELECT date_trunc('day', joined_at AT TIME ZONE 'UTC'), '', COUNT(*)
+		FROM users
+		WHERE joined_at IS NOT NULL AND joined_at >= $1
+		GROUP BY 1
+		ORDER BY 1
+	`, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanDayCounts(rows)
+}
+
+// scanDayCounts читает строки (день, ключ, число)
+func scanDayCounts(rows *sql.Rows) ([]storage.DayCount, error) {
+	var counts []storage.DayCount
+	for rows.Next() {
+		var c storage.DayCount
+		var key sql.NullString
+		if err := rows.Scan(&c.Day, &key, &c.Count); err != nil {
+			return nil, err
+		}
+		// timestamp без зоны приходит как UTC-время
+		c.Day = time.Date(c.Day.Year(), c.Day.Month(), c.Day.Day(), 0, 0, 0, 0, time.UTC)
+		c.Key = key.String
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
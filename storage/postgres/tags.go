@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"strings"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+	"github.com/FFFFFFFFFFj/trade-news-bot/tagger"
+)
+
+// Добавить алиас инструмента (название компании, тикер и т.п.)
+func (r *Repository) AddTagAlias(alias, ticker string) error {
+	_, err := r.db.Exec(`
+		INSERT INTO tag_aliases (alias, ticker)
+		VALUES ($1, $2)
+		ON CONFLICT (alias) DO UPDATE SET ticker = EXCLUDED.ticker
+	`, strings.ToLower(strings.TrimSpace(alias)), tagger.NormalizeTicker(ticker))
+	return err
+}
+
+// Удалить алиас
+func (r *Repository) RemoveTagAlias(alias string) error {
+	_, err := r.db.Exec(`DELETE FROM tag_aliases WHERE alias=$1`, strings.ToLower(strings.TrimSpace(alias)))
+	return err
+}
+
+// Получить словарь алиасов (алиас → тикер)
+func (r *Repository) GetTagAliases() (map[string]string, error) {
+	rows, err := r.db.Query(`SELECT alias, ticker FROM tag_aliases ORDER BY ticker, alias`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	aliases := make(map[string]string)
+	for rows.Next() {
+		var a, t string
+		if err := rows.Scan(&a, &t); err != nil {
+			return nil, err
+		}
+		aliases[a] = t
+	}
+	return aliases, nil
+}
+
+// Получить теги новости
+func (r *Repository) GetNewsTags(link string) ([]string, error) {
+	rows, err := r.db.Query(`SELECT ticker FROM news_tags WHERE news_link=$1 ORDER BY ticker`, link)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var t string
+		if err := rows.Scan(&t); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, nil
+}
+
+// Получить последние новости по тикеру
+func (r *Repository) GetNewsByTag(ticker string, limit int) ([]storage.NewsItem, error) {
+	rows, err := r.db.Query(`
+		SELECT `+newsColumns+`
+		FROM news n
+		JOIN news_tags t ON t.news_link = n.link
+		WHERE t.ticker = $1
+		ORDER BY n.pub_date DESC
+		LIMIT $2
+	`, tagger.NormalizeTicker(ticker), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanNews(rows)
+}
@@ -0,0 +1,269 @@
+// Package schema применяет версионированные SQL-миграции NNNN_name.up.sql / NNNN_name.down.sql.
+// Особенности СУБД (блокировка, DDL служебной таблицы) задаёт Dialect
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dialect — миграции конкретного бэкенда и особенности его СУБД
+type Dialect struct {
+	// FS содержит файлы migrations/*.sql
+	FS fs.FS
+	// CreateTable — DDL таблицы schema_migrations (version, name, applied_at)
+	CreateTable string
+	// Lock не даёт миграциям запускаться параллельно; nil — блокировка не нужна
+	Lock func(ctx context.Context, conn *sql.Conn) (unlock func(), err error)
+}
+
+// ErrSchemaAhead — в базе применены миграции, которых не знает этот бинарник
+var ErrSchemaAhead = errors.New("схема базы новее, чем поддерживает эта версия бота")
+
+// Migration — пара файлов NNNN_name.up.sql / NNNN_name.down.sql
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationState — состояние миграции для migrate status
+type MigrationState struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+// Load читает миграции из fsys, отсортированные по версии
+func Load(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, f := range files {
+		base := strings.TrimPrefix(f, "migrations/")
+		var direction string
+		switch {
+		case strings.HasSuffix(base, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(base, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("миграция %s: ожидается .up.sql или .down.sql", base)
+		}
+		name := strings.TrimSuffix(base, "."+direction+".sql")
+		num, rest, ok := strings.Cut(name, "_")
+		if !ok {
+			return nil, fmt.Errorf("миграция %s: ожидается NNNN_name", base)
+		}
+		version, err := strconv.ParseInt(num, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("миграция %s: %w", base, err)
+		}
+
+		body, err := fs.ReadFile(fsys, f)
+		if err != nil {
+			return nil, err
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: rest}
+			byVersion[version] = m
+		} else if m.Name != rest {
+			return nil, fmt.Errorf("миграция %d: разные имена %s и %s", version, m.Name, rest)
+		}
+		if direction == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("миграция %d_%s: нет up-файла", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up применяет все новые миграции. Возвращает ErrSchemaAhead,
+// если база уже обновлена более новой версией бота
+func Up(db *sql.DB, d Dialect) error {
+	return withLock(db, d, func(conn *sql.Conn) error {
+		migrations, applied, err := loadState(conn, d)
+		if err != nil {
+			return err
+		}
+		if err := checkSchemaAhead(migrations, applied); err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+			if err := applyMigration(conn, m.Version, m.Name, m.Up, true); err != nil {
+				return fmt.Errorf("миграция %d_%s: %w", m.Version, m.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Down откатывает steps последних применённых миграций
+func Down(db *sql.DB, d Dialect, steps int) error {
+	return withLock(db, d, func(conn *sql.Conn) error {
+		migrations, applied, err := loadState(conn, d)
+		if err != nil {
+			return err
+		}
+		if err := checkSchemaAhead(migrations, applied); err != nil {
+			return err
+		}
+		for i := len(migrations) - 1; i >= 0 && steps > 0; i-- {
+			m := migrations[i]
+			if _, ok := applied[m.Version]; !ok {
+				continue
+			}
+			if m.Down == "" {
+				return fmt.Errorf("миграция %d_%s: нет down-файла", m.Version, m.Name)
+			}
+			if err := applyMigration(conn, m.Version, m.Name, m.Down, false); err != nil {
+				return fmt.Errorf("откат %d_%s: %w", m.Version, m.Name, err)
+			}
+			steps--
+		}
+		return nil
+	})
+}
+
+// Status возвращает известные миграции и время их применения.
+// Версии из базы, которых нет в бинарнике, тоже попадают в список
+func Status(db *sql.DB, d Dialect) ([]MigrationState, error) {
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	migrations, applied, err := loadState(conn, d)
+	if err != nil {
+		return nil, err
+	}
+
+	var states []MigrationState
+	known := make(map[int64]bool)
+	for _, m := range migrations {
+		known[m.Version] = true
+		st := MigrationState{Version: m.Version, Name: m.Name}
+		if t, ok := applied[m.Version]; ok {
+			st.AppliedAt = &t
+		}
+		states = append(states, st)
+	}
+	for v, t := range applied {
+		if !known[v] {
+			states = append(states, MigrationState{Version: v, Name: "(неизвестна)", AppliedAt: &t})
+		}
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Version < states[j].Version })
+	return states, nil
+}
+
+func withLock(db *sql.DB, d Dialect, fn func(conn *sql.Conn) error) error {
+	ctx := context.Background()
+	// блокировка держится на конкретном соединении
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if d.Lock != nil {
+		unlock, err := d.Lock(ctx, conn)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	return fn(conn)
+}
+
+func loadState(conn *sql.Conn, d Dialect) ([]Migration, map[int64]time.Time, error) {
+	migrations, err := Load(d.FS)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx := context.Background()
+	if _, err := conn.ExecContext(ctx, d.CreateTable); err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var v int64
+		var t time.Time
+		if err := rows.Scan(&v, &t); err != nil {
+			return nil, nil, err
+		}
+		applied[v] = t
+	}
+	return migrations, applied, rows.Err()
+}
+
+func checkSchemaAhead(migrations []Migration, applied map[int64]time.Time) error {
+	var latest int64
+	if len(migrations) > 0 {
+		latest = migrations[len(migrations)-1].Version
+	}
+	for v := range applied {
+		if v > latest {
+			return fmt.Errorf("%w: версия базы %d, последняя известная %d", ErrSchemaAhead, v, latest)
+		}
+	}
+	return nil
+}
+
+// applyMigration выполняет SQL миграции и обновляет schema_migrations в одной транзакции
+func applyMigration(conn *sql.Conn, version int64, name, query string, up bool) error {
+	ctx := context.Background()
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return err
+	}
+	if up {
+		_, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, version, name)
+	} else {
+		_, err = tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version=$1`, version)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,198 @@
+package schema_test
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage/schema"
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage/sqlite"
+)
+
+// testMigrations — три миграции в формате NNNN_name.{up,down}.sql
+func testMigrations() fstest.MapFS {
+	return fstest.MapFS{
+		"migrations/0001_news.up.sql":      {Data: []byte(`CREATE TABLE news (link TEXT PRIMARY KEY)`)},
+		"migrations/0001_news.down.sql":    {Data: []byte(`DROP TABLE news`)},
+		"migrations/0002_title.up.sql":     {Data: []byte(`ALTER TABLE news ADD COLUMN title TEXT`)},
+		"migrations/0002_title.down.sql":   {Data: []byte(`ALTER TABLE news DROP COLUMN title`)},
+		"migrations/0003_sources.up.sql":   {Data: []byte(`CREATE TABLE sources (url TEXT PRIMARY KEY)`)},
+		"migrations/0003_sources.down.sql": {Data: []byte(`DROP TABLE sources`)},
+	}
+}
+
+// dialect — SQLite с заданным набором миграций
+func dialect(fsys fstest.MapFS) schema.Dialect {
+	d := sqlite.Schema
+	d.FS = fsys
+	return d
+}
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// applied возвращает применённые версии по Status
+func applied(t *testing.T, db *sql.DB, d schema.Dialect) []int64 {
+	t.Helper()
+	states, err := schema.Status(db, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versions []int64
+	for _, s := range states {
+		if s.AppliedAt != nil {
+			versions = append(versions, s.Version)
+		}
+	}
+	return versions
+}
+
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name=$1`, name).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n > 0
+}
+
+func TestUpDown(t *testing.T) {
+	db := openDB(t)
+	d := dialect(testMigrations())
+
+	tests := []struct {
+		name  string
+		run   func() error
+		want  []int64
+		table bool // есть ли таблица sources
+	}{
+		{"up", func() error { return schema.Up(db, d) }, []int64{1, 2, 3}, true},
+		{"повторный up ничего не делает", func() error { return schema.Up(db, d) }, []int64{1, 2, 3}, true},
+		{"down 2", func() error { return schema.Down(db, d, 2) }, []int64{1}, false},
+		{"снова up", func() error { return schema.Up(db, d) }, []int64{1, 2, 3}, true},
+		{"down больше, чем применено", func() error { return schema.Down(db, d, 10) }, nil, false},
+		{"down на пустой базе", func() error { return schema.Down(db, d, 1) }, nil, false},
+	}
+	for _, tt := range tests {
+		if err := tt.run(); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := applied(t, db, d); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: применены %v, ожидалось %v", tt.name, got, tt.want)
+		}
+		if got := tableExists(t, db, "sources"); got != tt.table {
+			t.Errorf("%s: таблица sources есть = %v, ожидалось %v", tt.name, got, tt.table)
+		}
+	}
+	if tableExists(t, db, "news") {
+		t.Error("после полного отката осталась таблица news")
+	}
+}
+
+// Ошибочная миграция не записывается в schema_migrations, предыдущие остаются
+func TestUpFailedMigration(t *testing.T) {
+	db := openDB(t)
+	fsys := testMigrations()
+	fsys["migrations/0002_title.up.sql"] = &fstest.MapFile{Data: []byte(`ALTER TABLE missing ADD COLUMN title TEXT`)}
+	d := dialect(fsys)
+
+	if err := schema.Up(db, d); err == nil {
+		t.Fatal("Up с ошибочной миграцией прошёл без ошибки")
+	}
+	if got := applied(t, db, d); !slices.Equal(got, []int64{1}) {
+		t.Errorf("применены %v, ожидалось [1]", got)
+	}
+	if tableExists(t, db, "sources") {
+		t.Error("миграции после ошибочной применены")
+	}
+}
+
+func TestSchemaAhead(t *testing.T) {
+	db := openDB(t)
+	if err := schema.Up(db, dialect(testMigrations())); err != nil {
+		t.Fatal(err)
+	}
+
+	// бинарник старой версии знает только две миграции
+	old := testMigrations()
+	delete(old, "migrations/0003_sources.up.sql")
+	delete(old, "migrations/0003_sources.down.sql")
+	d := dialect(old)
+
+	if err := schema.Up(db, d); !errors.Is(err, schema.ErrSchemaAhead) {
+		t.Errorf("Up: %v, ожидалось ErrSchemaAhead", err)
+	}
+	if err := schema.Down(db, d, 1); !errors.Is(err, schema.ErrSchemaAhead) {
+		t.Errorf("Down: %v, ожидалось ErrSchemaAhead", err)
+	}
+	// база не тронута, а Status показывает неизвестную версию
+	states, err := schema.Status(db, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 3 || states[2].Version != 3 || states[2].Name != "(неизвестна)" || states[2].AppliedAt == nil {
+		t.Errorf("Status: %+v", states)
+	}
+	if !tableExists(t, db, "sources") {
+		t.Error("таблица sources пропала после ErrSchemaAhead")
+	}
+}
+
+// Все миграции SQLite применяются, откатываются и применяются снова
+func TestSQLiteMigrations(t *testing.T) {
+	db := openDB(t)
+	all, err := schema.Load(sqlite.Schema.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := schema.Up(db, sqlite.Schema); err != nil {
+		t.Fatal(err)
+	}
+	if got := applied(t, db, sqlite.Schema); len(got) != len(all) {
+		t.Fatalf("применено %d миграций из %d", len(got), len(all))
+	}
+	if err := schema.Down(db, sqlite.Schema, len(all)); err != nil {
+		t.Fatal(err)
+	}
+	if got := applied(t, db, sqlite.Schema); len(got) != 0 {
+		t.Fatalf("после отката остались %v", got)
+	}
+	if err := schema.Up(db, sqlite.Schema); err != nil {
+		t.Fatalf("повторный up: %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		ok    bool
+	}{
+		{"нормальный набор", map[string]string{"0001_a.up.sql": "x", "0001_a.down.sql": "y", "0002_b.up.sql": "z"}, true},
+		{"нет up-файла", map[string]string{"0001_a.down.sql": "y"}, false},
+		{"нет номера", map[string]string{"init.up.sql": "x"}, false},
+		{"номер не число", map[string]string{"00x1_a.up.sql": "x"}, false},
+		{"разные имена", map[string]string{"0001_a.up.sql": "x", "0001_b.down.sql": "y"}, false},
+		{"неизвестное направление", map[string]string{"0001_a.sql": "x"}, false},
+	}
+	for _, tt := range tests {
+		fsys := fstest.MapFS{}
+		for name, body := range tt.files {
+			fsys["migrations/"+name] = &fstest.MapFile{Data: []byte(body)}
+		}
+		_, err := schema.Load(fsys)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: ошибка %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+// Записать действие админа
+func (r *Repository) AddAuditEntry(e storage.AuditEntry) error {
+	_, err := r.db.Exec(`
+		INSERT INTO audit_log (actor_id, action, args, result, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, e.ActorID, e.Action, e.Args, e.Result, now())
+	return err
+}
+
+// auditWhere строит условие по фильтру; параметры нумеруются с $1
+func auditWhere(f storage.AuditFilter) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	if f.ActorID != 0 {
+		args = append(args, f.ActorID)
+		conds = append(conds, fmt.Sprintf("actor_id = $%d", len(args)))
+	}
+	if f.Action != "" {
+		args = append(args, f.Action)
+		conds = append(conds, fmt.Sprintf("action = $%d", len(args)))
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
+
+// Страница журнала, от новых записей к старым
+func (r *Repository) GetAuditLog(f storage.AuditFilter, page, pageSize int) ([]storage.AuditEntry, error) {
+	where, args := auditWhere(f)
+	args = append(args, pageSize, (page-1)*pageSize)
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT id, actor_id, action, args, result, created_at
+		FROM audit_log
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []storage.AuditEntry
+	for rows.Next() {
+		var e storage.AuditEntry
+		if err := rows.Scan(&e.ID, &e.ActorID, &e.Action, &e.Args, &e.Result, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// Количество записей журнала по фильтру
+func (r *Repository) CountAuditLog(f storage.AuditFilter) (int, error) {
+	where, args := auditWhere(f)
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM audit_log `+where, args...).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+// Заменить расписание дайджестов пользователя
+func (r *Repository) SetUserAutopost(userID int64, schedules []storage.AutopostSchedule) error {
+	if err := storage.ValidateAutopost(schedules); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM autopost_schedules WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	for _, s := range schedules {
+		if _, err := tx.Exec(`
+			INSERT INTO autopost_schedules (user_id, time_of_day, weekdays, timezone, enabled)
+			VALUES ($1, $2, $3, $4, $5)
+		`, userID, s.TimeOfDay, int(s.Weekdays), s.Timezone, s.Enabled); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Расписание дайджестов пользователя
+func (r *Repository) GetUserAutopost(userID int64) ([]storage.AutopostSchedule, error) {
+	rows, err := r.db.Query(`
+		SELECT user_id, time_of_day, weekdays, timezone, enabled
+		FROM autopost_schedules
+		WHERE user_id = $1
+		ORDER BY time_of_day
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanAutopost(rows)
+}
+
+// Включённые расписания всех пользователей
+func (r *Repository) GetEnabledAutoposts() ([]storage.AutopostSchedule, error) {
+	rows, err := r.db.Query(`
+		SELECT a.user_id, a.time_of_day, a.weekdays, a.timezone, a.enabled
+		FROM autopost_schedules a
+		JOIN users u ON u.id = a.user_id AND u.status = 'active'
+		WHERE a.enabled
+		ORDER BY a.user_id, a.time_of_day
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanAutopost(rows)
+}
+
+func (r *Repository) GetAutopostUsersCount() (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(DISTINCT user_id) FROM autopost_schedules WHERE enabled`).Scan(&count)
+	return count, err
+}
+
+func scanAutopost(rows *sql.Rows) ([]storage.AutopostSchedule, error) {
+	var schedules []storage.AutopostSchedule
+	for rows.Next() {
+		var s storage.AutopostSchedule
+		var weekdays int
+		if err := rows.Scan(&s.UserID, &s.TimeOfDay, &weekdays, &s.Timezone, &s.Enabled); err != nil {
+			return nil, err
+		}
+		s.Weekdays = storage.Weekdays(weekdays)
+		schedules = append(schedules, s)
+	}
+	return schedules, rows.Err()
+}
@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// Файл базы по умолчанию
+const defaultPath = "trade-news-bot.db"
+
+// PathFromEnv возвращает путь к файлу базы из SQLITE_PATH
+func PathFromEnv() string {
+	if p := os.Getenv("SQLITE_PATH"); p != "" {
+		return p
+	}
+	return defaultPath
+}
+
+// Open открывает (и при необходимости создаёт) файл базы.
+// Время пишется в UTC в формате, который понимают функции даты SQLite,
+// поэтому его можно сравнивать строками
+func Open(path string) (*sql.DB, error) {
+	q := url.Values{}
+	q.Set("_time_format", "sqlite")
+	q.Add("_pragma", "foreign_keys(1)")
+	q.Add("_pragma", "journal_mode(WAL)")
+	q.Add("_pragma", "busy_timeout(5000)")
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+q.Encode())
+	if err != nil {
+		return nil, err
+	}
+	// SQLite допускает одного писателя; одно соединение избавляет от SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("не удалось открыть %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// now — текущее время для записи в базу
+func now() time.Time {
+	return time.Now().UTC()
+}
+
+// jsonArray передаёт список в запрос как JSON для json_each
+func jsonArray(items []string) string {
+	if items == nil {
+		items = []string{}
+	}
+	b, _ := json.Marshal(items)
+	return string(b)
+}
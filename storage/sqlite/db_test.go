@@ -0,0 +1,41 @@
+package sqlite
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage/schema"
+)
+
+// openTestDB создаёт базу со всеми миграциями во временном каталоге теста
+func openTestDB(tb testing.TB) *sql.DB {
+	tb.Helper()
+	db, err := Open(filepath.Join(tb.TempDir(), "test.db"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { db.Close() })
+	if err := schema.Up(db, Schema); err != nil {
+		tb.Fatal(err)
+	}
+	return db
+}
+
+// downTo откатывает миграции новее version
+func downTo(tb testing.TB, db *sql.DB, version int64) {
+	tb.Helper()
+	states, err := schema.Status(db, Schema)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	steps := 0
+	for _, s := range states {
+		if s.Version > version && s.AppliedAt != nil {
+			steps++
+		}
+	}
+	if err := schema.Down(db, Schema, steps); err != nil {
+		tb.Fatal(err)
+	}
+}
@@ -0,0 +1,42 @@
+package sqlite
+
+import "github.com/FFFFFFFFFFj/trade-news-bot/importance"
+
+// Добавить или обновить правило важности
+func (r *Repository) SetImportanceRule(keyword string, weight int) error {
+	_, err := r.db.Exec(`
+		INSERT INTO importance_rules (keyword, weight)
+		VALUES ($1, $2)
+		ON CONFLICT (keyword) DO UPDATE SET weight = EXCLUDED.weight
+	`, importance.NormalizeKeyword(keyword), weight)
+	return err
+}
+
+// Удалить правило важности
+func (r *Repository) RemoveImportanceRule(keyword string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM importance_rules WHERE keyword=$1`, importance.NormalizeKeyword(keyword))
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+// Получить правила важности
+func (r *Repository) GetImportanceRules() ([]importance.Rule, error) {
+	rows, err := r.db.Query(`SELECT keyword, weight FROM importance_rules ORDER BY weight DESC, keyword`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []importance.Rule
+	for rows.Next() {
+		var r importance.Rule
+		if err := rows.Scan(&r.Keyword, &r.Weight); err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
@@ -0,0 +1,23 @@
+package sqlite
+
+import (
+	"embed"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage/schema"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// Schema — миграции SQLite. Версии общих миграций совпадают с PostgreSQL,
+// 0019 и 0020 есть только здесь. Блокировка не нужна: база принадлежит одному процессу
+var Schema = schema.Dialect{
+	FS: migrationsFS,
+	CreateTable: `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%S+00:00', 'now'))
+		)
+	`,
+}
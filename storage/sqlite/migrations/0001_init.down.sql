@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS settings;
+DROP TABLE IF EXISTS user_autopost;
+DROP TABLE IF EXISTS user_read_news;
+DROP TABLE IF EXISTS news;
+DROP TABLE IF EXISTS subscriptions;
+DROP TABLE IF EXISTS sources;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS users (id BIGINT PRIMARY KEY);
+CREATE TABLE IF NOT EXISTS sources (url TEXT PRIMARY KEY);
+CREATE TABLE IF NOT EXISTS subscriptions (
+	user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+	source_url TEXT REFERENCES sources(url) ON DELETE CASCADE,
+	PRIMARY KEY (user_id, source_url)
+);
+CREATE TABLE IF NOT EXISTS news (
+	link TEXT PRIMARY KEY,
+	title TEXT,
+	pub_date TIMESTAMP,
+	source_url TEXT REFERENCES sources(url) ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS user_read_news (
+	user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+	news_id TEXT REFERENCES news(link) ON DELETE CASCADE,
+	PRIMARY KEY (user_id, news_id)
+);
+CREATE TABLE IF NOT EXISTS user_autopost (
+	user_id BIGINT PRIMARY KEY,
+	times TEXT
+);
+CREATE TABLE IF NOT EXISTS settings (
+	key TEXT PRIMARY KEY,
+	value TEXT
+);
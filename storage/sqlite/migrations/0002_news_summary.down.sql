@@ -0,0 +1,2 @@
+ALTER TABLE news DROP COLUMN summary;
+ALTER TABLE news DROP COLUMN description;
@@ -0,0 +1,2 @@
+ALTER TABLE news ADD COLUMN description TEXT;
+ALTER TABLE news ADD COLUMN summary TEXT;
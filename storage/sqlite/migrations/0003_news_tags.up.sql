@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS tag_aliases (
+	alias TEXT PRIMARY KEY,
+	ticker TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS news_tags (
+	news_link TEXT REFERENCES news(link) ON DELETE CASCADE,
+	ticker TEXT NOT NULL,
+	PRIMARY KEY (news_link, ticker)
+);
+CREATE INDEX IF NOT EXISTS news_tags_ticker_idx ON news_tags (ticker);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_prefs;
+ALTER TABLE news DROP COLUMN sentiment;
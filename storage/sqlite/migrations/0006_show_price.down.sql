@@ -0,0 +1 @@
+ALTER TABLE user_prefs DROP COLUMN show_price;
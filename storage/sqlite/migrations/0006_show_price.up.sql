@@ -0,0 +1 @@
+ALTER TABLE user_prefs ADD COLUMN show_price BOOLEAN NOT NULL DEFAULT FALSE;
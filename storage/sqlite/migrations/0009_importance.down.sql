@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS deferred_news;
+DROP TABLE IF EXISTS importance_rules;
+ALTER TABLE user_prefs DROP COLUMN importance_threshold;
+ALTER TABLE sources DROP COLUMN priority;
+ALTER TABLE news DROP COLUMN importance;
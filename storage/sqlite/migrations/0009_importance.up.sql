@@ -0,0 +1,12 @@
+ALTER TABLE news ADD COLUMN importance INT NOT NULL DEFAULT 0;
+ALTER TABLE sources ADD COLUMN priority INT NOT NULL DEFAULT 0;
+ALTER TABLE user_prefs ADD COLUMN importance_threshold INT NOT NULL DEFAULT 0;
+CREATE TABLE IF NOT EXISTS importance_rules (
+	keyword TEXT PRIMARY KEY,
+	weight INT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS deferred_news (
+	user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+	news_link TEXT REFERENCES news(link) ON DELETE CASCADE,
+	PRIMARY KEY (user_id, news_link)
+);
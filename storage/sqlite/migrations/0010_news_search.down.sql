@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS news_fts_update;
+DROP TRIGGER IF EXISTS news_fts_delete;
+DROP TRIGGER IF EXISTS news_fts_insert;
+DROP TABLE IF EXISTS news_fts;
@@ -0,0 +1,19 @@
+CREATE VIRTUAL TABLE IF NOT EXISTS news_fts USING fts5(
+	link UNINDEXED,
+	title,
+	description,
+	tokenize = 'unicode61 remove_diacritics 2'
+);
+INSERT INTO news_fts (link, title, description)
+	SELECT link, COALESCE(title, ''), COALESCE(description, '') FROM news;
+CREATE TRIGGER IF NOT EXISTS news_fts_insert AFTER INSERT ON news BEGIN
+	INSERT INTO news_fts (link, title, description)
+	VALUES (new.link, COALESCE(new.title, ''), COALESCE(new.description, ''));
+END;
+CREATE TRIGGER IF NOT EXISTS news_fts_delete AFTER DELETE ON news BEGIN
+	DELETE FROM news_fts WHERE link = old.link;
+END;
+CREATE TRIGGER IF NOT EXISTS news_fts_update AFTER UPDATE OF title, description ON news BEGIN
+	UPDATE news_fts SET title = COALESCE(new.title, ''), description = COALESCE(new.description, '')
+	WHERE link = new.link;
+END;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS news_source_pub_date_id_idx;
+DROP INDEX IF EXISTS news_pub_date_id_idx;
+CREATE INDEX IF NOT EXISTS news_pub_date_idx ON news (pub_date);
+DROP TRIGGER IF EXISTS news_set_id;
+DROP INDEX IF EXISTS news_id_idx;
+ALTER TABLE news DROP COLUMN id;
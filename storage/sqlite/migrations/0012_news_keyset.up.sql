@@ -0,0 +1,9 @@
+ALTER TABLE news ADD COLUMN id INTEGER;
+UPDATE news SET id = rowid;
+CREATE UNIQUE INDEX IF NOT EXISTS news_id_idx ON news (id);
+CREATE TRIGGER IF NOT EXISTS news_set_id AFTER INSERT ON news WHEN new.id IS NULL BEGIN
+	UPDATE news SET id = (SELECT COALESCE(MAX(id), 0) + 1 FROM news) WHERE link = new.link;
+END;
+DROP INDEX IF EXISTS news_pub_date_idx;
+CREATE INDEX IF NOT EXISTS news_pub_date_id_idx ON news (pub_date DESC, id DESC);
+CREATE INDEX IF NOT EXISTS news_source_pub_date_id_idx ON news (source_url, pub_date DESC, id DESC);
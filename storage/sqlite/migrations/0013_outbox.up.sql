@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS outbox (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	news_link TEXT NOT NULL REFERENCES news(link) ON DELETE CASCADE,
+	status TEXT NOT NULL DEFAULT 'pending',
+	attempts INT NOT NULL DEFAULT 0,
+	next_attempt_at TIMESTAMP NOT NULL,
+	claimed_until TIMESTAMP,
+	last_error TEXT,
+	created_at TIMESTAMP NOT NULL,
+	done_at TIMESTAMP,
+	UNIQUE (user_id, news_link)
+);
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (next_attempt_at, id) WHERE status = 'pending';
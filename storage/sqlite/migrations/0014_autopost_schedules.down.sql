@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS user_autopost (
+	user_id BIGINT PRIMARY KEY,
+	times TEXT
+);
+
+-- дни недели, пояс и выключенные записи в старом формате не выражаются
+INSERT INTO user_autopost (user_id, times)
+SELECT user_id, json_group_array(printf('%02d:%02d', time_of_day / 60, time_of_day % 60))
+FROM (SELECT * FROM autopost_schedules WHERE enabled ORDER BY user_id, time_of_day)
+GROUP BY user_id;
+
+DROP TABLE autopost_schedules;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS autopost_schedules (
+	user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	time_of_day INT NOT NULL CHECK (time_of_day >= 0 AND time_of_day < 1440),
+	weekdays INT NOT NULL DEFAULT 127 CHECK (weekdays > 0 AND weekdays < 128),
+	timezone TEXT NOT NULL DEFAULT 'Europe/Moscow',
+	enabled BOOLEAN NOT NULL DEFAULT TRUE,
+	PRIMARY KEY (user_id, time_of_day)
+);
+
+-- раньше время хранилось JSON-массивом строк HH:MM по Москве; некорректные значения отбрасываются
+INSERT INTO autopost_schedules (user_id, time_of_day)
+SELECT a.user_id, CAST(substr(t.value, 1, 2) AS INT) * 60 + CAST(substr(t.value, 4, 2) AS INT)
+FROM user_autopost a, json_each(CASE WHEN json_valid(a.times) THEN a.times ELSE '[]' END) t
+WHERE t.value GLOB '[0-2][0-9]:[0-5][0-9]'
+AND substr(t.value, 1, 2) < '24'
+AND a.user_id IN (SELECT id FROM users)
+ON CONFLICT DO NOTHING;
+
+DROP TABLE user_autopost;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS users_last_seen_idx;
+DROP INDEX IF EXISTS users_username_idx;
+ALTER TABLE users DROP COLUMN status;
+ALTER TABLE users DROP COLUMN last_seen_at;
+ALTER TABLE users DROP COLUMN joined_at;
+ALTER TABLE users DROP COLUMN language_code;
+ALTER TABLE users DROP COLUMN last_name;
+ALTER TABLE users DROP COLUMN first_name;
+ALTER TABLE users DROP COLUMN username;
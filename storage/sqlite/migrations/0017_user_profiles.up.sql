@@ -0,0 +1,12 @@
+ALTER TABLE users ADD COLUMN username TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN first_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN last_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN language_code TEXT NOT NULL DEFAULT '';
+-- дата прихода тех, кто был до профилей, неизвестна: берётся первый ценовой алерт, иначе NULL.
+-- Новых пользователей joined_at заполняет бот
+ALTER TABLE users ADD COLUMN joined_at TIMESTAMP;
+UPDATE users SET joined_at = (SELECT MIN(created_at) FROM price_alerts WHERE user_id = users.id);
+ALTER TABLE users ADD COLUMN last_seen_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN status TEXT NOT NULL DEFAULT 'active';
+CREATE INDEX IF NOT EXISTS users_username_idx ON users (lower(username));
+CREATE INDEX IF NOT EXISTS users_last_seen_idx ON users (last_seen_at);
@@ -0,0 +1,7 @@
+ALTER TABLE sources DROP COLUMN sort_order;
+ALTER TABLE sources DROP COLUMN added_at;
+ALTER TABLE sources DROP COLUMN added_by;
+ALTER TABLE sources DROP COLUMN language;
+ALTER TABLE sources DROP COLUMN category;
+ALTER TABLE sources DROP COLUMN description;
+ALTER TABLE sources DROP COLUMN title;
@@ -0,0 +1,7 @@
+ALTER TABLE sources ADD COLUMN title TEXT NOT NULL DEFAULT '';
+ALTER TABLE sources ADD COLUMN description TEXT NOT NULL DEFAULT '';
+ALTER TABLE sources ADD COLUMN category TEXT NOT NULL DEFAULT '';
+ALTER TABLE sources ADD COLUMN language TEXT NOT NULL DEFAULT '';
+ALTER TABLE sources ADD COLUMN added_by BIGINT;
+ALTER TABLE sources ADD COLUMN added_at TIMESTAMP;
+ALTER TABLE sources ADD COLUMN sort_order INT NOT NULL DEFAULT 0;
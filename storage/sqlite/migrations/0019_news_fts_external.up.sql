@@ -0,0 +1,28 @@
+DROP TRIGGER IF EXISTS news_fts_update;
+DROP TRIGGER IF EXISTS news_fts_delete;
+DROP TRIGGER IF EXISTS news_fts_insert;
+DROP TABLE IF EXISTS news_fts;
+-- external content: текст хранится только в news, индекс связан с news.id,
+-- поэтому удаление и обновление находят запись индекса по rowid без полного просмотра
+CREATE VIRTUAL TABLE IF NOT EXISTS news_fts USING fts5(
+	title,
+	description,
+	content = 'news',
+	content_rowid = 'id',
+	tokenize = 'unicode61 remove_diacritics 2'
+);
+INSERT INTO news_fts (news_fts) VALUES ('rebuild');
+CREATE TRIGGER IF NOT EXISTS news_fts_insert AFTER INSERT ON news WHEN new.id IS NOT NULL BEGIN
+	INSERT INTO news_fts (rowid, title, description) VALUES (new.id, new.title, new.description);
+END;
+-- без явного id его проставляет news_set_id уже после вставки
+CREATE TRIGGER IF NOT EXISTS news_fts_set_id AFTER UPDATE OF id ON news WHEN old.id IS NULL BEGIN
+	INSERT INTO news_fts (rowid, title, description) VALUES (new.id, new.title, new.description);
+END;
+CREATE TRIGGER IF NOT EXISTS news_fts_delete AFTER DELETE ON news WHEN old.id IS NOT NULL BEGIN
+	INSERT INTO news_fts (news_fts, rowid, title, description) VALUES ('delete', old.id, old.title, old.description);
+END;
+CREATE TRIGGER IF NOT EXISTS news_fts_update AFTER UPDATE OF title, description ON news WHEN old.id IS NOT NULL BEGIN
+	INSERT INTO news_fts (news_fts, rowid, title, description) VALUES ('delete', old.id, old.title, old.description);
+	INSERT INTO news_fts (rowid, title, description) VALUES (new.id, new.title, new.description);
+END;
@@ -0,0 +1,32 @@
+-- значения остаются в формате Go: функции даты SQLite их понимают
+CREATE TABLE price_alerts_old (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+	ticker TEXT NOT NULL,
+	op TEXT NOT NULL,
+	level DOUBLE PRECISION NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	triggered_at TIMESTAMP
+);
+INSERT INTO price_alerts_old SELECT id, user_id, ticker, op, level, created_at, triggered_at FROM price_alerts;
+DROP TABLE price_alerts;
+ALTER TABLE price_alerts_old RENAME TO price_alerts;
+
+CREATE TABLE news_archive_old (
+	link TEXT PRIMARY KEY,
+	title TEXT,
+	pub_date TIMESTAMP,
+	source_url TEXT,
+	description TEXT,
+	summary TEXT,
+	sentiment REAL NOT NULL DEFAULT 0,
+	importance INT NOT NULL DEFAULT 0,
+	tags TEXT NOT NULL DEFAULT '',
+	archived_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO news_archive_old
+SELECT link, title, pub_date, source_url, description, summary, sentiment, importance, tags, archived_at
+FROM news_archive;
+DROP TABLE news_archive;
+ALTER TABLE news_archive_old RENAME TO news_archive;
+CREATE INDEX IF NOT EXISTS news_archive_pub_date_idx ON news_archive (pub_date);
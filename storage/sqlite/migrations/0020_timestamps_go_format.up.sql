@@ -0,0 +1,43 @@
+-- CURRENT_TIMESTAMP пишет «2006-01-02 15:04:05» без пояса, а драйвер — время Go
+-- с «+00:00», и строковое сравнение таких значений ошибается на равных моментах.
+-- Умолчания переводятся на формат Go (таблицы пересоздаются: SQLite не меняет
+-- DEFAULT у колонки), старым значениям дописывается пояс
+CREATE TABLE price_alerts_new (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+	ticker TEXT NOT NULL,
+	op TEXT NOT NULL,
+	level DOUBLE PRECISION NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%S+00:00', 'now')),
+	triggered_at TIMESTAMP
+);
+INSERT INTO price_alerts_new (id, user_id, ticker, op, level, created_at, triggered_at)
+SELECT id, user_id, ticker, op, level,
+	CASE WHEN length(created_at) = 19 THEN created_at || '+00:00' ELSE created_at END,
+	triggered_at
+FROM price_alerts;
+DROP TABLE price_alerts;
+ALTER TABLE price_alerts_new RENAME TO price_alerts;
+
+CREATE TABLE news_archive_new (
+	link TEXT PRIMARY KEY,
+	title TEXT,
+	pub_date TIMESTAMP,
+	source_url TEXT,
+	description TEXT,
+	summary TEXT,
+	sentiment REAL NOT NULL DEFAULT 0,
+	importance INT NOT NULL DEFAULT 0,
+	tags TEXT NOT NULL DEFAULT '',
+	archived_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%S+00:00', 'now'))
+);
+INSERT INTO news_archive_new (link, title, pub_date, source_url, description, summary, sentiment, importance, tags, archived_at)
+SELECT link, title, pub_date, source_url, description, summary, sentiment, importance, tags,
+	CASE WHEN length(archived_at) = 19 THEN archived_at || '+00:00' ELSE archived_at END
+FROM news_archive;
+DROP TABLE news_archive;
+ALTER TABLE news_archive_new RENAME TO news_archive;
+CREATE INDEX IF NOT EXISTS news_archive_pub_date_idx ON news_archive (pub_date);
+
+-- joined_at старых пользователей взят из created_at алертов
+UPDATE users SET joined_at = joined_at || '+00:00' WHERE length(joined_at) = 19;
@@ -0,0 +1,159 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+const (
+	benchNewsRows = 1000000
+	benchSources  = 10
+	benchUserID   = 1
+	benchPageSize = 10
+)
+
+// seedBenchNews заполняет базу benchNewsRows новостями по benchSources источникам;
+// пользователь benchUserID подписан на половину источников
+func seedBenchNews(b *testing.B) *sql.DB {
+	b.Helper()
+	db := openTestDB(b)
+	tx, err := db.Begin()
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`INSERT INTO users (id, joined_at) VALUES ($1, $2)`, benchUserID, now()); err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < benchSources; i++ {
+		src := fmt.Sprintf("https://source%d.example/rss", i)
+		if _, err := tx.Exec(`INSERT INTO sources (url) VALUES ($1)`, src); err != nil {
+			b.Fatal(err)
+		}
+		if i%2 == 0 {
+			if _, err := tx.Exec(`INSERT INTO subscriptions (user_id, source_url) VALUES ($1, $2)`, benchUserID, src); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO news (id, link, title, pub_date, source_url, description, summary, sentiment, importance)
+		VALUES ($1, $2, $3, $4, $5, '', '', 0, 0)`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < benchNewsRows; i++ {
+		_, err := stmt.Exec(i+1, fmt.Sprintf("https://news.example/%d", i), fmt.Sprintf("news %d", i),
+			base.Add(-time.Duration(i)*time.Minute), fmt.Sprintf("https://source%d.example/rss", i%benchSources))
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		b.Fatal(err)
+	}
+	if _, err := db.Exec(`ANALYZE`); err != nil {
+		b.Fatal(err)
+	}
+	return db
+}
+
+func TestKnownLinks(t *testing.T) {
+	store := New(openTestDB(t))
+	src := "https://source.example/rss"
+	if err := store.Sources.AddSource(src, 0); err != nil {
+		t.Fatal(err)
+	}
+	for _, link := range []string{"https://news.example/1", "https://news.example/2"} {
+		if _, err := store.News.SaveNews(storage.NewsItem{Title: link, Link: link, PubDate: time.Now(), Source: src}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	known, err := store.News.KnownLinks([]string{"https://news.example/2", "https://news.example/3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(known) != 1 || !known["https://news.example/2"] {
+		t.Errorf("KnownLinks = %v", known)
+	}
+	if known, err := store.News.KnownLinks(nil); err != nil || len(known) != 0 {
+		t.Errorf("KnownLinks(nil) = %v, %v", known, err)
+	}
+}
+
+// Старый вариант /latest: COUNT(*) для счётчика страниц и OFFSET на каждой странице
+func latestByOffset(db *sql.DB, userID int64, offset, limit int) ([]storage.NewsItem, error) {
+	var total int
+	if err := db.QueryRow(`
+		SELECT COUNT(*)
+		FROM news n
+		WHERE n.source_url IN (SELECT source_url FROM subscriptions WHERE user_id = $1)
+		AND `+sentimentCondition, userID).Scan(&total); err != nil {
+		return nil, err
+	}
+	rows, err := db.Query(`
+		SELECT `+newsColumns+`
+		FROM news n
+		WHERE n.source_url IN (SELECT source_url FROM subscriptions WHERE user_id = $1)
+		AND `+sentimentCondition+`
+		ORDER BY n.pub_date DESC
+		LIMIT $2 OFFSET $3`, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanNews(rows)
+}
+
+// BenchmarkGetLatestNewsForUser сравнивает keyset-пагинацию /latest со старой
+// OFFSET/COUNT(*) на первой странице и глубоко в ленте. Заполнение базы
+// занимает несколько минут: go test -run '^$' -bench GetLatestNewsForUser -timeout 30m ./storage/sqlite
+func BenchmarkGetLatestNewsForUser(b *testing.B) {
+	db := seedBenchNews(b)
+	store := New(db)
+
+	// подписан на половину источников, так что глубокая страница — около середины
+	deepOffset := benchNewsRows / 2 * 8 / 10
+	var deep storage.NewsCursor
+	if err := db.QueryRow(`
+		SELECT n.pub_date, n.id FROM news n
+		WHERE n.source_url IN (SELECT source_url FROM subscriptions WHERE user_id = $1)
+		ORDER BY n.pub_date DESC, n.id DESC
+		LIMIT 1 OFFSET $2`, benchUserID, deepOffset).Scan(&deep.PubDate, &deep.ID); err != nil {
+		b.Fatal(err)
+	}
+
+	keyset := func(cursor storage.NewsCursor, dir storage.PageDirection) func(*testing.B) {
+		return func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				news, err := store.News.GetLatestNewsForUser(benchUserID, cursor, dir, benchPageSize)
+				if err != nil || len(news) != benchPageSize {
+					b.Fatalf("получено %d новостей: %v", len(news), err)
+				}
+			}
+		}
+	}
+	offset := func(offset int) func(*testing.B) {
+		return func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				news, err := latestByOffset(db, benchUserID, offset, benchPageSize)
+				if err != nil || len(news) != benchPageSize {
+					b.Fatalf("получено %d новостей: %v", len(news), err)
+				}
+			}
+		}
+	}
+
+	b.Run("keyset/first", keyset(storage.NewsCursor{}, storage.PageFirst))
+	b.Run("keyset/deep", keyset(deep, storage.PageAfter))
+	b.Run("offset/first", offset(0))
+	b.Run("offset/deep", offset(deepOffset))
+}
@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+// Забрать доставки, которым пора уйти, и зарезервировать их на lease.
+// Соединение с SQLite одно, поэтому выборка и резерв идут в одной транзакции
+func (r *Repository) ClaimDeliveries(limit int, lease time.Duration) ([]storage.Delivery, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	current := now()
+	rows, err := tx.Query(`
+		SELECT o.id, o.user_id, o.attempts,
+			EXISTS (SELECT 1 FROM user_read_news u WHERE u.user_id = o.user_id AND u.news_id = o.news_link),
+			`+newsColumns+`
+		FROM outbox o
+		JOIN news n ON n.link = o.news_link
+		WHERE o.status = 'pending' AND o.next_attempt_at <= $1
+		AND (o.claimed_until IS NULL OR o.claimed_until < $1)
+		ORDER BY o.next_attempt_at, o.id
+		LIMIT $2
+	`, current, limit)
+	if err != nil {
+		return nil, err
+	}
+	var deliveries []storage.Delivery
+	for rows.Next() {
+		var d storage.Delivery
+		if d.News, err = scanNewsRow(rows, &d.ID, &d.UserID, &d.Attempts, &d.Read); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	rows.Close()
+
+	for _, d := range deliveries {
+		if _, err := tx.Exec(`UPDATE outbox SET claimed_until = $2 WHERE id = $1`, d.ID, current.Add(lease)); err != nil {
+			return nil, err
+		}
+	}
+	return deliveries, tx.Commit()
+}
+
+// Завершить доставку
+func (r *Repository) CompleteDelivery(id int64, status, lastError string) error {
+	_, err := r.db.Exec(`
+		UPDATE outbox
+		SET status = $2, last_error = NULLIF($3, ''), claimed_until = NULL, done_at = $4
+		WHERE id = $1
+	`, id, status, lastError, now())
+	return err
+}
+
+// Вернуть доставку в очередь после ошибки
+func (r *Repository) RetryDelivery(id int64, next time.Time, lastError string) error {
+	_, err := r.db.Exec(`
+		UPDATE outbox
+		SET attempts = attempts + 1, next_attempt_at = $2, last_error = $3, claimed_until = NULL
+		WHERE id = $1
+	`, id, next.UTC(), lastError)
+	return err
+}
+
+// Количество доставок, ожидающих отправки
+func (r *Repository) CountPendingDeliveries() (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM outbox WHERE status = 'pending'`).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,36 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+// Repository реализует все репозитории storage поверх SQLite
+type Repository struct {
+	db *sql.DB
+}
+
+// New создаёт хранилище поверх подключения к PostgreSQL
+func New(db *sql.DB) *storage.Store {
+	r := &Repository{db: db}
+	return &storage.Store{
+		Users:         r,
+		Sources:       r,
+		Subscriptions: r,
+		News:          r,
+		Autopost:      r,
+		Settings:      r,
+		Tags:          r,
+		Watchlist:     r,
+		Prefs:         r,
+		Alerts:        r,
+		Calendar:      r,
+		Importance:    r,
+		Retention:     r,
+		Reads:         r,
+		Outbox:        r,
+		Audit:         r,
+		Stats:         r,
+	}
+}
@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+// Удалить (или перенести в архив) новости старше before
+func (r *Repository) PruneNews(before time.Time, archive, dryRun bool) (storage.RetentionStats, error) {
+	var stats storage.RetentionStats
+	before = before.UTC()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return stats, err
+	}
+	defer tx.Rollback()
+
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM news WHERE pub_date < $1`, before).Scan(&stats.News); err != nil {
+		return stats, err
+	}
+	if err := tx.QueryRow(`
+		SELECT COUNT(*)
+		FROM user_read_news u
+		JOIN news n ON n.link = u.news_id
+		WHERE n.pub_date < $1
+	`, before).Scan(&stats.ReadMarkers); err != nil {
+		return stats, err
+	}
+	if dryRun || stats.News == 0 {
+		return stats, nil
+	}
+
+	if archive {
+		if _, err := tx.Exec(`
+			INSERT INTO news_archive (link, title, pub_date, source_url, description, summary, sentiment, importance, tags, archived_at)
+			SELECT n.link, n.title, n.pub_date, n.source_url, n.description, n.summary, n.sentiment, n.importance, `+tagsColumn+`, $2
+			FROM news n
+			WHERE n.pub_date < $1
+			ON CONFLICT (link) DO NOTHING
+		`, before, now()); err != nil {
+			return stats, err
+		}
+	}
+	if _, err := tx.Exec(`
+		DELETE FROM user_read_news
+		WHERE news_id IN (SELECT link FROM news WHERE pub_date < $1)
+	`, before); err != nil {
+		return stats, err
+	}
+	// теги, отложенная доставка и outbox удаляются каскадно
+	if _, err := tx.Exec(`DELETE FROM news WHERE pub_date < $1`, before); err != nil {
+		return stats, err
+	}
+	return stats, tx.Commit()
+}
+
+// Обновить статистику планировщика
+func (r *Repository) Analyze() error {
+	_, err := r.db.Exec(`ANALYZE`)
+	return err
+}
@@ -0,0 +1,91 @@
+package sqlite
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+// Условие поиска: $1 — запрос FTS5, $2 — подстрока URL источника, $3 и $4 — интервал дат
+const searchCondition = `news_fts MATCH $1
+	AND ($2 = '' OR instr(n.source_url, $2) > 0)
+	AND ($3 IS NULL OR n.pub_date >= $3)
+	AND ($4 IS NULL OR n.pub_date < $4)`
+
+// Найти новости по запросу, самые релевантные первыми.
+// Заголовок весит больше описания
+func (r *Repository) SearchNews(q storage.SearchQuery, page, pageSize int) ([]storage.NewsItem, error) {
+	match := ftsQuery(q.Text)
+	if match == "" {
+		return nil, nil
+	}
+	offset := (page - 1) * pageSize
+
+	rows, err := r.db.Query(`
+		SELECT `+newsColumns+`
+		FROM news_fts
+		JOIN news n ON n.id = news_fts.rowid
+		WHERE `+searchCondition+`
+		ORDER BY bm25(news_fts, 10.0, 1.0), n.pub_date DESC
+		LIMIT $5 OFFSET $6
+	`, match, q.Source, nullTime(q.From), nullTime(q.To), pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanNews(rows)
+}
+
+// Количество найденных новостей
+func (r *Repository) CountSearchNews(q storage.SearchQuery) (int, error) {
+	match := ftsQuery(q.Text)
+	if match == "" {
+		return 0, nil
+	}
+	var count int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM news_fts
+		JOIN news n ON n.id = news_fts.rowid
+		WHERE `+searchCondition+`
+	`, match, q.Source, nullTime(q.From), nullTime(q.To)).Scan(&count)
+	return count, err
+}
+
+// ftsQuery переводит запрос пользователя в синтаксис FTS5: все слова
+// обязательны и ищутся по префиксу (стемминга для русского в SQLite нет),
+// слова с минусом исключаются
+func ftsQuery(text string) string {
+	var include, exclude []string
+	for _, w := range strings.Fields(text) {
+		neg := strings.HasPrefix(w, "-")
+		w = strings.Trim(w, `-"'`)
+		if w == "" {
+			continue
+		}
+		term := `"` + strings.ReplaceAll(w, `"`, `""`) + `"*`
+		if neg {
+			exclude = append(exclude, term)
+		} else {
+			include = append(include, term)
+		}
+	}
+	if len(include) == 0 {
+		return ""
+	}
+	q := strings.Join(include, " ")
+	for _, t := range exclude {
+		q += " NOT " + t
+	}
+	return q
+}
+
+// nullTime — NULL для нулевого времени
+func nullTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
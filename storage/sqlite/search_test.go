@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+// Индекс поиска следует за вставкой, правкой и удалением новостей
+func TestSearchIndexFollowsNews(t *testing.T) {
+	db := openTestDB(t)
+	store := New(db)
+	src := "https://source.example/rss"
+	if err := store.Sources.AddSource(src, 0); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for _, n := range []storage.NewsItem{
+		{Title: "Сбербанк повысил дивиденды", Link: "https://news.example/1", PubDate: now.AddDate(0, 0, -10)},
+		{Title: "Газпром снизил добычу", Link: "https://news.example/2", PubDate: now.AddDate(0, 0, -10)},
+		{Title: "Сбербанк отчитался о прибыли", Link: "https://news.example/3", PubDate: now},
+	} {
+		n.Source = src
+		if _, err := store.News.SaveNews(n, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count := func(text string) int {
+		t.Helper()
+		n, err := store.News.CountSearchNews(storage.SearchQuery{Text: text})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	if got := count("сбербанк"); got != 2 {
+		t.Fatalf("до очистки найдено %d, ожидалось 2", got)
+	}
+
+	if _, err := store.Retention.PruneNews(now.AddDate(0, 0, -5), false, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := count("сбербанк"); got != 1 {
+		t.Errorf("после очистки найдено %d, ожидалось 1", got)
+	}
+	if got := count("газпром"); got != 0 {
+		t.Errorf("удалённая новость осталась в индексе: %d", got)
+	}
+
+	if _, err := db.Exec(`UPDATE news SET title = 'ВТБ отчитался о прибыли' WHERE link = 'https://news.example/3'`); err != nil {
+		t.Fatal(err)
+	}
+	if got := count("сбербанк"); got != 0 {
+		t.Errorf("после правки заголовка найдено по старому: %d", got)
+	}
+	if got := count("втб"); got != 1 {
+		t.Errorf("после правки заголовка по новому найдено %d", got)
+	}
+
+	if _, err := db.Exec(`INSERT INTO news_fts (news_fts) VALUES ('integrity-check')`); err != nil {
+		t.Errorf("индекс расходится с news: %v", err)
+	}
+}
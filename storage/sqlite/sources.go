@@ -0,0 +1,134 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+const sourceColumns = `url, title, description, category, language, added_by, added_at, sort_order, priority`
+
+// Добавление источника
+func (r *Repository) AddSource(url string, addedBy int64) error {
+	_, err := r.db.Exec(`INSERT INTO sources (url, added_by, added_at) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`, url, addedBy, now())
+	return err
+}
+
+// Удаление источника
+func (r *Repository) RemoveSource(url string) error {
+	_, err := r.db.Exec(`DELETE FROM sources WHERE url=$1`, url)
+	return err
+}
+
+// Получение всех источников
+func (r *Repository) GetAllSources() ([]string, error) {
+	rows, err := r.db.Query(`SELECT url FROM sources`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sources []string
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, err
+		}
+		sources = append(sources, url)
+	}
+	return sources, nil
+}
+
+// Источники с метаданными для списков и меню
+func (r *Repository) GetSources() ([]storage.Source, error) {
+	rows, err := r.db.Query(`
+		SELECT ` + sourceColumns + ` FROM sources
+		ORDER BY sort_order DESC, title = '', title, url`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sources []storage.Source
+	for rows.Next() {
+		s, err := scanSource(rows)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, s)
+	}
+	return sources, rows.Err()
+}
+
+func (r *Repository) GetSource(url string) (storage.Source, bool, error) {
+	s, err := scanSource(r.db.QueryRow(`SELECT `+sourceColumns+` FROM sources WHERE url = $1`, url))
+	if err == sql.ErrNoRows {
+		return s, false, nil
+	}
+	return s, err == nil, err
+}
+
+// Обновить метаданные источника. Возвращает false, если источника нет
+func (r *Repository) UpdateSource(s storage.Source) (bool, error) {
+	res, err := r.db.Exec(`
+		UPDATE sources SET title = $2, description = $3, category = $4, language = $5, sort_order = $6
+		WHERE url = $1`,
+		s.URL, s.Title, s.Description, s.Category, s.Language, s.SortOrder)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+// Заполнить пустые метаданные источника данными из фида
+func (r *Repository) FillSourceMeta(url, title, description, language string) error {
+	_, err := r.db.Exec(`
+		UPDATE sources SET
+			title = CASE WHEN title = '' THEN $2 ELSE title END,
+			description = CASE WHEN description = '' THEN $3 ELSE description END,
+			language = CASE WHEN language = '' THEN $4 ELSE language END
+		WHERE url = $1`, url, title, description, language)
+	return err
+}
+
+// scanSource читает строку с колонками sourceColumns
+func scanSource(row interface{ Scan(...interface{}) error }) (storage.Source, error) {
+	var s storage.Source
+	var addedBy sql.NullInt64
+	var addedAt sql.NullTime
+	err := row.Scan(&s.URL, &s.Title, &s.Description, &s.Category, &s.Language, &addedBy, &addedAt, &s.SortOrder, &s.Priority)
+	s.AddedBy = addedBy.Int64
+	s.AddedAt = addedAt.Time
+	return s, err
+}
+
+// Установить приоритет источника. Возвращает false, если источника нет
+func (r *Repository) SetSourcePriority(url string, priority int) (bool, error) {
+	res, err := r.db.Exec(`UPDATE sources SET priority=$2 WHERE url=$1`, url, priority)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+// Получить приоритеты источников
+func (r *Repository) GetSourcePriorities() (map[string]int, error) {
+	rows, err := r.db.Query(`SELECT url, priority FROM sources`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	priorities := make(map[string]int)
+	for rows.Next() {
+		var u string
+		var p int
+		if err := rows.Scan(&u, &p); err != nil {
+			return nil, err
+		}
+		priorities[u] = p
+	}
+	return priorities, nil
+}
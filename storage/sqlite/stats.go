@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+func (r *Repository) CountNewsByDay(since time.Time) ([]storage.DayCount, error) {
+	rows, err := r.db.Query(`
+		SELECT date(pub_date) AS day, source_url, COUNT(*)
+		FROM (
+			SELECT pub_date, source_url FROM news WHERE pub_date >= $1
+			UNION ALL
+			SELECT pub_date, source_url FROM news_archive WHERE pub_date >= $1
+		)
+		GROUP BY day, source_url
+		ORDER BY day, source_url
+	`, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanDayCounts(rows)
+}
+
+func (r *Repository) CountPushesByDay(since time.Time) ([]storage.DayCount, error) {
+	rows, err := r.db.Query(`
+		SELECT date(done_at) AS day, '', COUNT(*)
+		FROM outbox
+		WHERE status = $1 AND done_at >= $2
+		GROUP BY day
+		ORDER BY day
+	`, storage.DeliverySent, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanDayCounts(rows)
+}
+
+func (r *Repository) CountNewUsersByDay(since time.Time) ([]storage.DayCount, error) {
+	rows, err := r.db.Query(`
+		SELECT date(joined_at) AS day, '', COUNT(*)
+		FROM users
+		WHERE joined_at IS NOT NULL AND joined_at >= $1
+		GROUP BY day
+		ORDER BY day
+	`, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanDayCounts(rows)
+}
+
+// scanDayCounts читает строки (день YYYY-MM-DD, ключ, число)
+func scanDayCounts(rows *sql.Rows) ([]storage.DayCount, error) {
+	var counts []storage.DayCount
+	for rows.Next() {
+		var c storage.DayCount
+		var day string
+		var key sql.NullString
+		if err := rows.Scan(&day, &key, &c.Count); err != nil {
+			return nil, err
+		}
+		d, err := time.Parse(time.DateOnly, day)
+		if err != nil {
+			return nil, err
+		}
+		c.Day = d
+		c.Key = key.String
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
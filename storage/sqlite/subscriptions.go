@@ -0,0 +1,46 @@
+package sqlite
+
+// Подписать пользователя на источник
+func (r *Repository) Subscribe(userID int64, url string) (bool, error) {
+	res, err := r.db.Exec(`INSERT INTO subscriptions (user_id, source_url) VALUES ($1, $2) ON CONFLICT DO NOTHING`, userID, url)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+// Отписать пользователя от источника
+func (r *Repository) Unsubscribe(userID int64, url string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM subscriptions WHERE user_id=$1 AND source_url=$2`, userID, url)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+// Источники, на которые подписан пользователь
+func (r *Repository) GetUserSubscriptions(userID int64) ([]string, error) {
+	rows, err := r.db.Query(`SELECT source_url FROM subscriptions WHERE user_id=$1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sources []string
+	for rows.Next() {
+		var src string
+		if err := rows.Scan(&src); err != nil {
+			return nil, err
+		}
+		sources = append(sources, src)
+	}
+	return sources, nil
+}
+
+func (r *Repository) GetUserSubscriptionCount(userID int64) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM subscriptions WHERE user_id=$1`, userID).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,152 @@
+package sqlite
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage/schema"
+)
+
+// Формат, в котором драйвер пишет время Go в UTC
+var goTimestamp = regexp.MustCompile(`^\d{4}-\d\d-\d\d \d\d:\d\d:\d\d(\.\d+)?\+00:00$`)
+
+// checkTimestamps проверяет формат всех непустых значений колонок TIMESTAMP
+func checkTimestamps(t *testing.T, db *sql.DB) {
+	t.Helper()
+	rows, err := db.Query(`
+		SELECT m.name, c.name
+		FROM sqlite_master m, pragma_table_info(m.name) c
+		WHERE m.type = 'table' AND c.type = 'TIMESTAMP'
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type column struct{ table, name string }
+	var columns []column
+	for rows.Next() {
+		var c column
+		if err := rows.Scan(&c.table, &c.name); err != nil {
+			t.Fatal(err)
+		}
+		columns = append(columns, c)
+	}
+	rows.Close()
+
+	for _, c := range columns {
+		// CAST: иначе драйвер разберёт значение в time.Time и формат потеряется
+		values, err := db.Query(`SELECT CAST(` + c.name + ` AS TEXT) FROM ` + c.table + ` WHERE ` + c.name + ` IS NOT NULL`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for values.Next() {
+			var v string
+			if err := values.Scan(&v); err != nil {
+				t.Fatal(err)
+			}
+			if !goTimestamp.MatchString(v) {
+				t.Errorf("%s.%s: %q не в формате Go", c.table, c.name, v)
+			}
+		}
+		values.Close()
+	}
+}
+
+// Время, записанное умолчанием колонки и из Go, хранится в одном формате
+// и одинаково сравнивается с параметрами запросов
+func TestTimestampsWrittenBothWays(t *testing.T) {
+	db := openTestDB(t)
+	store := New(db)
+	src := "https://source.example/rss"
+	if err := store.Sources.AddSource(src, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Users.TouchUser(storage.UserProfile{ID: 1, FirstName: "Test"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Subscriptions.Subscribe(1, src); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().AddDate(0, 0, -30)
+	for _, n := range []storage.NewsItem{
+		{Title: "Старая", Link: "https://news.example/old", PubDate: old},
+		{Title: "Новая", Link: "https://news.example/new", PubDate: time.Now()},
+	} {
+		n.Source = src
+		if _, err := store.News.SaveNews(n, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	goID, err := store.Alerts.AddPriceAlert(storage.PriceAlert{UserID: 1, Ticker: "SBER", Op: ">", Level: 300})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Alerts.MarkPriceAlertTriggered(goID); err != nil {
+		t.Fatal(err)
+	}
+	// created_at из умолчания колонки
+	if _, err := db.Exec(`INSERT INTO price_alerts (user_id, ticker, op, level) VALUES (1, 'GAZP', '<', 150)`); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Settings.SetSetting("test", "1", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Audit.AddAuditEntry(storage.AuditEntry{ActorID: 1, Action: "test"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Outbox.ClaimDeliveries(10, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Retention.PruneNews(old.Add(time.Hour), true, false); err != nil {
+		t.Fatal(err)
+	}
+
+	checkTimestamps(t, db)
+
+	// граница фильтра, равная значению, включает строку при любом способе записи
+	for _, where := range []string{"ticker = 'SBER'", "ticker = 'GAZP'"} {
+		var created time.Time
+		if err := db.QueryRow(`SELECT created_at FROM price_alerts WHERE ` + where).Scan(&created); err != nil {
+			t.Fatal(err)
+		}
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM price_alerts WHERE `+where+` AND created_at >= $1`, created.UTC()).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		if n != 1 {
+			t.Errorf("%s: created_at >= собственного значения не выполнилось", where)
+		}
+	}
+}
+
+// Значения, записанные CURRENT_TIMESTAMP до миграции 0020, переводятся в формат Go
+func TestTimestampsMigrated(t *testing.T) {
+	db := openTestDB(t)
+	downTo(t, db, 19)
+	for _, q := range []string{
+		`INSERT INTO users (id) VALUES (1)`,
+		`INSERT INTO price_alerts (user_id, ticker, op, level, created_at) VALUES (1, 'SBER', '>', 300, '2024-03-01 10:00:00')`,
+		`UPDATE users SET joined_at = '2024-03-01 10:00:00'`,
+		`INSERT INTO news_archive (link, title) VALUES ('https://news.example/1', 'Новость')`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := schema.Up(db, Schema); err != nil {
+		t.Fatal(err)
+	}
+
+	checkTimestamps(t, db)
+
+	at := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	n, err := New(db).Users.CountUsersJoinedSince(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("пользователь, пришедший ровно в %v, не учтён", at)
+	}
+}
@@ -0,0 +1,239 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+)
+
+const userColumns = `id, username, first_name, last_name, language_code, joined_at, last_seen_at, status`
+
+// Создать пользователя или обновить его профиль
+func (r *Repository) TouchUser(p storage.UserProfile) error {
+	_, err := r.db.Exec(`
+		INSERT INTO users (id, username, first_name, last_name, language_code, joined_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (id) DO UPDATE SET
+			username = EXCLUDED.username,
+			first_name = EXCLUDED.first_name,
+			last_name = EXCLUDED.last_name,
+			language_code = COALESCE(NULLIF(EXCLUDED.language_code, ''), users.language_code),
+			last_seen_at = EXCLUDED.last_seen_at,
+			status = 'active'`,
+		p.ID, p.Username, p.FirstName, p.LastName, p.LanguageCode, now())
+	return err
+}
+
+func (r *Repository) EnsureUser(id int64) error {
+	_, err := r.db.Exec(`INSERT INTO users (id, joined_at) VALUES ($1, $2) ON CONFLICT DO NOTHING`, id, now())
+	return err
+}
+
+func (r *Repository) GetUser(id int64) (storage.UserProfile, bool, error) {
+	p, err := scanUser(r.db.QueryRow(`SELECT `+userColumns+` FROM users WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return p, false, nil
+	}
+	return p, err == nil, err
+}
+
+// FindUsers: lower и LIKE в SQLite не меняют регистр не-ASCII букв,
+// поэтому имя ищется и в нижнем регистре, и как введено
+func (r *Repository) FindUsers(query string, limit int) ([]storage.UserProfile, error) {
+	query = strings.TrimPrefix(query, "@")
+	pattern := "%" + strings.ToLower(query) + "%"
+	rows, err := r.db.Query(`
+		SELECT `+userColumns+` FROM users
+		WHERE lower(username) LIKE $1 OR lower(first_name || ' ' || last_name) LIKE $1
+			OR first_name || ' ' || last_name LIKE $3
+		ORDER BY last_seen_at DESC NULLS LAST, id
+		LIMIT $2`, pattern, limit, "%"+query+"%")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []storage.UserProfile
+	for rows.Next() {
+		p, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, p)
+	}
+	return users, rows.Err()
+}
+
+// scanUser читает строку с колонками userColumns
+func scanUser(row interface{ Scan(...interface{}) error }) (storage.UserProfile, error) {
+	var p storage.UserProfile
+	var joined, lastSeen sql.NullTime
+	err := row.Scan(&p.ID, &p.Username, &p.FirstName, &p.LastName, &p.LanguageCode, &joined, &lastSeen, &p.Status)
+	p.JoinedAt = joined.Time
+	p.LastSeenAt = lastSeen.Time
+	return p, err
+}
+
+// Таблицы с данными пользователя по колонке user_id. key — колонки уникального
+// ключа с user_id; у таблиц без такого ключа пусто
+var userTables = []struct {
+	name string
+	key  []string
+}{
+	{"subscriptions", []string{"user_id", "source_url"}},
+	{"user_read_news", []string{"user_id", "news_id"}},
+	{"watchlist", []string{"user_id", "ticker"}},
+	{"user_prefs", []string{"user_id"}},
+	{"price_alerts", nil},
+	{"calendar_subscriptions", []string{"user_id", "category"}},
+	{"event_notifications", []string{"user_id", "event_uid", "kind"}},
+	{"deferred_news", []string{"user_id", "news_link"}},
+	{"outbox", []string{"user_id", "news_link"}},
+	{"autopost_schedules", []string{"user_id", "time_of_day"}},
+}
+
+func (r *Repository) DeactivateUser(id int64, status string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE users SET status = $2 WHERE id = $1`, id, status); err != nil {
+		return err
+	}
+	// не копим доставки, которые всё равно не дойдут
+	if _, err := tx.Exec(`
+		UPDATE outbox SET status = $2, last_error = $3, done_at = $4
+		WHERE user_id = $1 AND status = 'pending'
+	`, id, storage.DeliverySkipped, "user "+status, now()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) MigrateUserChat(oldID, newID int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT INTO users (id, username, first_name, last_name, language_code, joined_at, last_seen_at, status)
+		SELECT $2, username, first_name, last_name, language_code, joined_at, last_seen_at, status
+		FROM users WHERE id = $1
+		ON CONFLICT (id) DO NOTHING
+	`, oldID, newID); err != nil {
+		return err
+	}
+	// строки переносятся UPDATE, чтобы id (доставки в работе, алерты) не менялись.
+	// Если у нового чата уже есть строка с тем же ключом, остаётся она,
+	// а строка старого чата удаляется
+	for _, t := range userTables {
+		if t.key != nil {
+			same := ""
+			for _, col := range t.key {
+				if col != "user_id" {
+					same += ` AND n.` + col + ` = ` + t.name + `.` + col
+				}
+			}
+			if _, err := tx.Exec(`
+				DELETE FROM `+t.name+` WHERE user_id = $1
+				AND EXISTS (SELECT 1 FROM `+t.name+` n WHERE n.user_id = $2`+same+`)
+			`, oldID, newID); err != nil {
+				return fmt.Errorf("%s: %w", t.name, err)
+			}
+		}
+		if _, err := tx.Exec(`UPDATE `+t.name+` SET user_id = $2 WHERE user_id = $1`, oldID, newID); err != nil {
+			return fmt.Errorf("%s: %w", t.name, err)
+		}
+	}
+	if _, err := tx.Exec(`DELETE FROM users WHERE id = $1`, oldID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) DeleteUser(id int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// явно, а не только через ON DELETE CASCADE: не у всех таблиц есть внешний ключ
+	for _, t := range userTables {
+		if _, err := tx.Exec(`DELETE FROM `+t.name+` WHERE user_id = $1`, id); err != nil {
+			return fmt.Errorf("%s: %w", t.name, err)
+		}
+	}
+	if _, err := tx.Exec(`DELETE FROM users WHERE id = $1`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) GetReachableUsers() ([]int64, error) {
+	rows, err := r.db.Query(`SELECT id FROM users WHERE status = 'active'`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		users = append(users, id)
+	}
+	return users, nil
+}
+
+func (r *Repository) GetUsersCount() (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM users`).Scan(&count)
+	return count, err
+}
+
+func (r *Repository) GetActiveUsersCount() (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(DISTINCT user_id) FROM subscriptions`).Scan(&count)
+	return count, err
+}
+
+func (r *Repository) CountUsersSeenSince(t time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM users WHERE last_seen_at >= $1`, t.UTC()).Scan(&count)
+	return count, err
+}
+
+func (r *Repository) CountUsersJoinedSince(t time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM users WHERE joined_at IS NOT NULL AND joined_at >= $1`, t.UTC()).Scan(&count)
+	return count, err
+}
+
+func (r *Repository) GetUserLanguages() (map[string]int, error) {
+	rows, err := r.db.Query(`SELECT language_code, COUNT(*) FROM users GROUP BY language_code`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	langs := make(map[string]int)
+	for rows.Next() {
+		var lang string
+		var count int
+		if err := rows.Scan(&lang, &count); err != nil {
+			return nil, err
+		}
+		langs[lang] = count
+	}
+	return langs, rows.Err()
+}
@@ -0,0 +1,202 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage"
+	"github.com/FFFFFFFFFFj/trade-news-bot/storage/schema"
+)
+
+// Пользователи, бывшие до профилей, получают дату прихода только из первого
+// алерта; без него дата остаётся неизвестной и не попадает в статистику
+func TestJoinedAtBackfill(t *testing.T) {
+	db := openTestDB(t)
+	// до 0017 профилей ещё нет
+	downTo(t, db, 16)
+	alertAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	for _, q := range []string{
+		`INSERT INTO users (id) VALUES (1), (2)`,
+		`INSERT INTO price_alerts (user_id, ticker, op, level, created_at) VALUES (1, 'SBER', '>', 300, '2024-03-01 10:00:00')`,
+		`INSERT INTO price_alerts (user_id, ticker, op, level, created_at) VALUES (1, 'GAZP', '<', 150, '2024-05-01 10:00:00')`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := schema.Up(db, Schema); err != nil {
+		t.Fatal(err)
+	}
+
+	store := New(db)
+	p, _, err := store.Users.GetUser(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.JoinedAt.Equal(alertAt) {
+		t.Errorf("дата прихода по первому алерту: %v, ожидалось %v", p.JoinedAt, alertAt)
+	}
+	p, _, err = store.Users.GetUser(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.JoinedAt.IsZero() {
+		t.Errorf("дата прихода без алертов должна быть неизвестна: %v", p.JoinedAt)
+	}
+
+	if err := store.Users.EnsureUser(3); err != nil {
+		t.Fatal(err)
+	}
+	n, err := store.Users.CountUsersJoinedSince(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("пришедших с известной датой %d, ожидалось 2", n)
+	}
+	days, err := store.Stats.CountNewUsersByDay(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for _, d := range days {
+		total += d.Count
+	}
+	if total != 2 {
+		t.Errorf("новых по дням %d, ожидалось 2: %+v", total, days)
+	}
+}
+
+// Перенос на чат, у которого уже есть свои данные: совпадающие строки
+// не мешают переносу, остальные переходят к новому чату
+func TestMigrateUserChatIntoExisting(t *testing.T) {
+	db := openTestDB(t)
+	store := New(db)
+	const oldID, newID = -100, -1001
+	srcA, srcB := "https://a.example/rss", "https://b.example/rss"
+	for _, src := range []string{srcA, srcB} {
+		if err := store.Sources.AddSource(src, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, id := range []int64{oldID, newID} {
+		if err := store.Users.EnsureUser(id); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.Subscriptions.Subscribe(id, srcA); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Watchlist.AddToWatchlist(id, "SBER"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Prefs.SetSentimentFilter(newID, "positive"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Prefs.SetSentimentFilter(oldID, "negative"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Subscriptions.Subscribe(oldID, srcB); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Watchlist.AddToWatchlist(oldID, "GAZP"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Alerts.AddPriceAlert(storage.PriceAlert{UserID: oldID, Ticker: "SBER", Op: ">", Level: 300}); err != nil {
+		t.Fatal(err)
+	}
+	// новость по общему источнику попадает в outbox обоим чатам
+	if _, err := store.News.SaveNews(storage.NewsItem{Title: "Новость", Link: "https://news.example/1", Source: srcA, PubDate: time.Now()}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Reads.MarkNewsRead(oldID, []string{"https://news.example/1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Reads.MarkNewsRead(newID, []string{"https://news.example/1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Users.MigrateUserChat(oldID, newID); err != nil {
+		t.Fatal(err)
+	}
+
+	if subs, _ := store.Subscriptions.GetUserSubscriptions(newID); len(subs) != 2 {
+		t.Errorf("подписки нового чата: %v", subs)
+	}
+	if tickers, _ := store.Watchlist.GetWatchlist(newID); len(tickers) != 2 {
+		t.Errorf("watchlist нового чата: %v", tickers)
+	}
+	if f, _ := store.Prefs.GetSentimentFilter(newID); f != "positive" {
+		t.Errorf("фильтр нового чата перезаписан: %q", f)
+	}
+	if alerts, _ := store.Alerts.GetUserPriceAlerts(newID); len(alerts) != 1 {
+		t.Errorf("алерты нового чата: %+v", alerts)
+	}
+	if _, ok, _ := store.Users.GetUser(oldID); ok {
+		t.Error("старый чат не удалён")
+	}
+	for _, table := range []string{"subscriptions", "watchlist", "user_prefs", "user_read_news", "outbox", "price_alerts"} {
+		var left int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM `+table+` WHERE user_id = $1`, oldID).Scan(&left); err != nil {
+			t.Fatal(err)
+		}
+		if left != 0 {
+			t.Errorf("%s: у старого чата осталось %d строк", table, left)
+		}
+	}
+}
+
+// Чат переезжает посреди доставки: отправитель закрывает её по прежнему id,
+// и повторно она не уходит
+func TestMigrateUserChatDuringDelivery(t *testing.T) {
+	db := openTestDB(t)
+	store := New(db)
+	const oldID, newID = -100, -1001
+	src := "https://a.example/rss"
+	if err := store.Sources.AddSource(src, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Users.EnsureUser(oldID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Subscriptions.Subscribe(oldID, src); err != nil {
+		t.Fatal(err)
+	}
+	alertID, err := store.Alerts.AddPriceAlert(storage.PriceAlert{UserID: oldID, Ticker: "SBER", Op: ">", Level: 300})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.News.SaveNews(storage.NewsItem{Title: "Новость", Link: "https://news.example/1", Source: src, PubDate: time.Now()}, ""); err != nil {
+		t.Fatal(err)
+	}
+	claimed, err := store.Outbox.ClaimDeliveries(10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claimed) != 1 {
+		t.Fatalf("забрано доставок: %d", len(claimed))
+	}
+
+	if err := store.Users.MigrateUserChat(oldID, newID); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Outbox.CompleteDelivery(claimed[0].ID, storage.DeliverySent, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	var userID int64
+	var status string
+	if err := db.QueryRow(`SELECT user_id, status FROM outbox WHERE id = $1`, claimed[0].ID).Scan(&userID, &status); err != nil {
+		t.Fatal(err)
+	}
+	if userID != newID || status != storage.DeliverySent {
+		t.Errorf("доставка после переезда: user_id %d, статус %q", userID, status)
+	}
+	if n, _ := store.Outbox.CountPendingDeliveries(); n != 0 {
+		t.Errorf("в очереди осталось %d доставок", n)
+	}
+	alerts, _ := store.Alerts.GetUserPriceAlerts(newID)
+	if len(alerts) != 1 || alerts[0].ID != alertID {
+		t.Errorf("алерт после переезда: %+v, ожидался id %d", alerts, alertID)
+	}
+}
@@ -0,0 +1,39 @@
+package sqlite
+
+// Добавить тикер в watchlist пользователя
+func (r *Repository) AddToWatchlist(userID int64, ticker string) error {
+	_, err := r.db.Exec(`
+		INSERT INTO watchlist (user_id, ticker)
+		VALUES ($1, $2) ON CONFLICT DO NOTHING
+	`, userID, ticker)
+	return err
+}
+
+// Удалить тикер из watchlist. Возвращает false, если тикера там не было
+func (r *Repository) RemoveFromWatchlist(userID int64, ticker string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM watchlist WHERE user_id=$1 AND ticker=$2`, userID, ticker)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+// Получить watchlist пользователя
+func (r *Repository) GetWatchlist(userID int64) ([]string, error) {
+	rows, err := r.db.Query(`SELECT ticker FROM watchlist WHERE user_id=$1 ORDER BY ticker`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tickers []string
+	for rows.Next() {
+		var t string
+		if err := rows.Scan(&t); err != nil {
+			return nil, err
+		}
+		tickers = append(tickers, t)
+	}
+	return tickers, nil
+}
@@ -0,0 +1,299 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/calendar"
+	"github.com/FFFFFFFFFFj/trade-news-bot/importance"
+	"github.com/FFFFFFFFFFj/trade-news-bot/tagger"
+)
+
+// Store объединяет репозитории, с которыми работает бот.
+// Реализации: storage/postgres и storage/memory
+type Store struct {
+	Users         Users
+	Sources       Sources
+	Subscriptions Subscriptions
+	News          News
+	Autopost      Autopost
+	Settings      Settings
+	Tags          Tags
+	Watchlist     Watchlist
+	Prefs         Prefs
+	Alerts        Alerts
+	Calendar      Calendar
+	Importance    ImportanceRules
+	Retention     Retention
+	Reads         Reads
+	Outbox        Outbox
+	Audit         Audit
+	Stats         Stats
+}
+
+// Users — пользователи бота
+type Users interface {
+	// TouchUser создаёт пользователя или обновляет его профиль из Telegram,
+	// отмечает время последней активности и снова делает его активным.
+	// Пустой LanguageCode не затирает сохранённый
+	TouchUser(p UserProfile) error
+	// EnsureUser добавляет чат без профиля (группу), если его ещё нет
+	EnsureUser(id int64) error
+	// DeactivateUser ставит статус неактивного пользователя (UserBlocked, UserDeactivated)
+	// и закрывает его ожидающие доставки в outbox
+	DeactivateUser(id int64, status string) error
+	// DeleteUser удаляет пользователя и все его данные
+	DeleteUser(id int64) error
+	// MigrateUserChat переносит пользователя и все его данные на новый chat ID
+	// (группа стала супергруппой)
+	MigrateUserChat(oldID, newID int64) error
+	// GetUser возвращает профиль; ok == false, если пользователя нет
+	GetUser(id int64) (p UserProfile, ok bool, err error)
+	// FindUsers ищет по части username, имени или фамилии
+	FindUsers(query string, limit int) ([]UserProfile, error)
+	// GetReachableUsers — активные пользователи, которым можно писать
+	GetReachableUsers() ([]int64, error)
+	GetUsersCount() (int, error)
+	// GetActiveUsersCount — число пользователей хотя бы с одной подпиской
+	GetActiveUsersCount() (int, error)
+	CountUsersSeenSince(t time.Time) (int, error)
+	// CountUsersJoinedSince не учитывает пользователей без даты прихода
+	CountUsersJoinedSince(t time.Time) (int, error)
+	// GetUserLanguages — число пользователей по language_code, пустой код — ""
+	GetUserLanguages() (map[string]int, error)
+}
+
+// Sources — RSS-источники
+type Sources interface {
+	AddSource(url string, addedBy int64) error
+	RemoveSource(url string) error
+	GetAllSources() ([]string, error)
+	// GetSources — источники с метаданными по SortOrder, затем по названию;
+	// источники без названия в конце
+	GetSources() ([]Source, error)
+	GetSource(url string) (Source, bool, error)
+	// UpdateSource сохраняет название, описание, категорию, язык и SortOrder.
+	// Возвращает false, если источника нет
+	UpdateSource(s Source) (bool, error)
+	// FillSourceMeta заполняет только пустые название, описание и язык
+	// (данными из фида)
+	FillSourceMeta(url, title, description, language string) error
+	// SetSourcePriority возвращает false, если источника нет
+	SetSourcePriority(url string, priority int) (bool, error)
+	GetSourcePriorities() (map[string]int, error)
+}
+
+// Subscriptions — подписки пользователей на источники
+type Subscriptions interface {
+	// Subscribe возвращает false, если подписка уже была
+	Subscribe(userID int64, url string) (bool, error)
+	// Unsubscribe возвращает false, если подписки не было
+	Unsubscribe(userID int64, url string) (bool, error)
+	GetUserSubscriptions(userID int64) ([]string, error)
+	GetUserSubscriptionCount(userID int64) (int, error)
+}
+
+// News — новости, их теги и отложенная доставка
+type News interface {
+	// SaveNews сохраняет новость с тегами и в той же транзакции ставит её в outbox
+	// подписчикам источника и пользователям, у которых в watchlist есть один из её
+	// тикеров; inserted == false, если она уже была
+	SaveNews(n NewsItem, description string) (inserted bool, err error)
+	// KnownLinks — какие из ссылок уже сохранены: их незачем заново размечать и оценивать
+	KnownLinks(links []string) (map[string]bool, error)
+	// GetCoverage — сколько других источников за CoverageWindow писали о тех же тикерах
+	GetCoverage(source string, tags []string) (int, error)
+
+	// Выборки учитывают фильтр тональности пользователя
+	CountLatestNewsForUser(userID int64) (int, error)
+	// GetLatestNewsForUser — страница новостей из подписок относительно курсора,
+	// всегда от новых к старым
+	GetLatestNewsForUser(userID int64, cursor NewsCursor, dir PageDirection, limit int) ([]NewsItem, error)
+	// GetNewsBetweenForUser — новости из подписок, вышедшие в интервале [from, to]
+	GetNewsBetweenForUser(userID int64, from, to time.Time, limit int) ([]NewsItem, error)
+
+	// SearchNews — полнотекстовый поиск по заголовкам и описаниям, по убыванию релевантности
+	SearchNews(q SearchQuery, page, pageSize int) ([]NewsItem, error)
+	CountSearchNews(q SearchQuery) (int, error)
+
+	GetNewsTags(link string) ([]string, error)
+	GetNewsByTag(ticker string, limit int) ([]NewsItem, error)
+
+	// DeferNews откладывает новость до следующего дайджеста
+	DeferNews(userID int64, link string) error
+	// PopDeferredNews забирает отложенные новости (они удаляются из очереди)
+	PopDeferredNews(userID int64) ([]NewsItem, error)
+}
+
+// Autopost — расписание дайджестов
+type Autopost interface {
+	// SetUserAutopost заменяет расписание пользователя; каждая запись
+	// проверяется через Validate, время дня не повторяется
+	SetUserAutopost(userID int64, schedules []AutopostSchedule) error
+	// GetUserAutopost — расписание пользователя по возрастанию времени
+	GetUserAutopost(userID int64) ([]AutopostSchedule, error)
+	// GetEnabledAutoposts — включённые расписания всех пользователей
+	GetEnabledAutoposts() ([]AutopostSchedule, error)
+	// GetAutopostUsersCount — число пользователей с включённым расписанием
+	GetAutopostUsersCount() (int, error)
+}
+
+// Settings — настройки бота (ключ/значение); известные ключи описаны в пакете settings
+type Settings interface {
+	// SetSetting сохраняет значение и в той же транзакции пишет изменение в историю
+	SetSetting(key, value string, adminID int64) error
+	GetSetting(key string) (string, error)
+	GetAllSettings() (map[string]string, error)
+	// GetSettingHistory — последние изменения настройки, от новых к старым
+	GetSettingHistory(key string, limit int) ([]SettingChange, error)
+}
+
+// Tags — словарь алиасов инструментов (алиас → тикер)
+type Tags interface {
+	AddTagAlias(alias, ticker string) error
+	RemoveTagAlias(alias string) error
+	GetTagAliases() (map[string]string, error)
+}
+
+// Watchlist — тикеры, за которыми следит пользователь
+type Watchlist interface {
+	AddToWatchlist(userID int64, ticker string) error
+	// RemoveFromWatchlist возвращает false, если тикера не было
+	RemoveFromWatchlist(userID int64, ticker string) (bool, error)
+	GetWatchlist(userID int64) ([]string, error)
+}
+
+// Prefs — персональные настройки пользователей
+type Prefs interface {
+	SetSentimentFilter(userID int64, filter string) error
+	GetSentimentFilter(userID int64) (string, error)
+	// GetSentimentFilters — фильтры пользователей, у которых он отличается от all
+	GetSentimentFilters() (map[int64]string, error)
+
+	SetShowPrice(userID int64, show bool) error
+	GetShowPrice(userID int64) (bool, error)
+	GetShowPriceUsers() (map[int64]bool, error)
+
+	SetTimezone(userID int64, tz string) error
+	GetTimezone(userID int64) (string, error)
+
+	SetImportanceThreshold(userID int64, threshold int) error
+	GetImportanceThreshold(userID int64) (int, error)
+	// GetImportanceThresholds — пороги пользователей, у которых он не 0
+	GetImportanceThresholds() (map[int64]int, error)
+}
+
+// Alerts — ценовые алерты
+type Alerts interface {
+	AddPriceAlert(a PriceAlert) (int64, error)
+	DeletePriceAlert(userID, id int64) error
+	GetUserPriceAlerts(userID int64) ([]PriceAlert, error)
+	GetActivePriceAlerts() ([]PriceAlert, error)
+	// MarkPriceAlertTriggered возвращает false, если алерт уже сработал
+	MarkPriceAlertTriggered(id int64) (bool, error)
+}
+
+// Calendar — экономический календарь и напоминания
+type Calendar interface {
+	AddCalendarSource(url string) error
+	// RemoveCalendarSource удаляет календарь вместе с его событиями
+	RemoveCalendarSource(url string) error
+	GetCalendarSources() ([]string, error)
+
+	// SaveEvents сохраняет события, обновляя известные по UID
+	SaveEvents(source string, events []calendar.Event) error
+	// GetEventsBetween — события в интервале [from, to)
+	GetEventsBetween(from, to time.Time) ([]calendar.Event, error)
+	GetEventCategories() ([]string, error)
+
+	SubscribeCalendar(userID int64, category string, remindBefore int) error
+	UnsubscribeCalendar(userID int64, category string) (bool, error)
+	// GetCalendarSubscriptions — категория → за сколько минут напоминать
+	GetCalendarSubscriptions(userID int64) (map[string]int, error)
+
+	// GetDueEventReminders — напоминания, которые пора отправить.
+	// У событий на весь день нет времени начала, о них не напоминают
+	GetDueEventReminders() ([]EventNotification, error)
+	// GetDueEventFollowups — события, начавшиеся не менее after назад (но не раньше суток),
+	// по которым ещё не отправлена подборка новостей
+	GetDueEventFollowups(after time.Duration) ([]EventNotification, error)
+	// MarkEventNotified возвращает false, если уведомление уже было
+	MarkEventNotified(userID int64, uid, kind string) (bool, error)
+}
+
+// ImportanceRules — правила важности новостей
+type ImportanceRules interface {
+	SetImportanceRule(keyword string, weight int) error
+	RemoveImportanceRule(keyword string) (bool, error)
+	GetImportanceRules() ([]importance.Rule, error)
+}
+
+// Retention — очистка старых новостей
+type Retention interface {
+	// PruneNews удаляет новости, вышедшие раньше before, вместе с отметками
+	// о прочтении. archive — сначала скопировать их в news_archive,
+	// dryRun — только посчитать, ничего не меняя
+	PruneNews(before time.Time, archive, dryRun bool) (RetentionStats, error)
+	// Analyze обновляет статистику планировщика после очистки
+	Analyze() error
+}
+
+// Reads — отметки о прочтении новостей (user_read_news)
+type Reads interface {
+	// MarkNewsRead отмечает новости прочитанными; уже отмеченные пропускаются
+	MarkNewsRead(userID int64, links []string) error
+	// MarkAllNewsRead отмечает прочитанными все новости из подписок пользователя
+	MarkAllNewsRead(userID int64) (int, error)
+	// Выборки непрочитанного учитывают подписки и фильтр тональности, от новых к старым
+	CountUnreadNewsForUser(userID int64) (int, error)
+	GetUnreadNewsForUser(userID int64, limit int) ([]NewsItem, error)
+	// GetReadNews — ссылки на прочитанные новости пользователя
+	GetReadNews(userID int64) ([]string, error)
+}
+
+// Outbox — очередь доставки новых новостей пользователям.
+// Доставка «хотя бы один раз»: строка остаётся в очереди, пока отправитель
+// не отметит результат, а (пользователь, новость) ставится в неё только однажды
+type Outbox interface {
+	// ClaimDeliveries забирает до limit доставок, которым пора уйти, и резервирует
+	// их на lease: если отправитель упадёт, по истечении lease их заберут снова
+	ClaimDeliveries(limit int, lease time.Duration) ([]Delivery, error)
+	// CompleteDelivery завершает доставку с итогом status (DeliverySent и т. п.)
+	CompleteDelivery(id int64, status, lastError string) error
+	// RetryDelivery возвращает доставку в очередь после ошибки: попытка
+	// засчитывается, следующая — не раньше next
+	RetryDelivery(id int64, next time.Time, lastError string) error
+	// CountPendingDeliveries — сколько доставок ждёт отправки
+	CountPendingDeliveries() (int, error)
+}
+
+// Audit — журнал действий админов
+type Audit interface {
+	AddAuditEntry(e AuditEntry) error
+	// GetAuditLog — записи по фильтру от новых к старым, page начинается с 1
+	GetAuditLog(f AuditFilter, page, pageSize int) ([]AuditEntry, error)
+	CountAuditLog(f AuditFilter) (int, error)
+}
+
+// LoadTagger создаёт теггер с алиасами из хранилища
+func LoadTagger(tags Tags) *tagger.Tagger {
+	aliases, _ := tags.GetTagAliases()
+	return tagger.New(aliases)
+}
+
+// LoadImportanceScorer создаёт оценщик важности с правилами и приоритетами источников
+func LoadImportanceScorer(rules ImportanceRules, sources Sources) *importance.Scorer {
+	r, _ := rules.GetImportanceRules()
+	priorities, _ := sources.GetSourcePriorities()
+	return importance.New(r, priorities)
+}
+
+// Stats — счётчики по дням (UTC) для графиков /stats
+type Stats interface {
+	// CountNewsByDay — новости по источникам и дням публикации, включая архив
+	CountNewsByDay(since time.Time) ([]DayCount, error)
+	// CountPushesByDay — отправленные push-уведомления по дням. Записи outbox
+	// удаляются вместе с новостями, поэтому история не длиннее срока хранения новостей
+	CountPushesByDay(since time.Time) ([]DayCount, error)
+	// CountNewUsersByDay — новые пользователи по дням; без даты прихода не учитываются
+	CountNewUsersByDay(since time.Time) ([]DayCount, error)
+}
@@ -0,0 +1,274 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FFFFFFFFFFj/trade-news-bot/calendar"
+)
+
+// За какой период считается охват темы другими источниками
+const CoverageWindow = 6 * time.Hour
+
+// Часовой пояс по умолчанию
+const DefaultTimezone = "Europe/Moscow"
+
+// Виды уведомлений о событиях
+const (
+	EventNotifyReminder = "reminder"
+	EventNotifyNews     = "news"
+)
+
+// Категория подписки на все события
+const AllCategories = "all"
+
+// NewsItem представляет новость
+type NewsItem struct {
+	ID         int64
+	Title      string
+	Link       string
+	PubDate    time.Time
+	Source     string
+	Summary    string
+	Tags       []string
+	Sentiment  float64
+	Importance int
+}
+
+// Delivery — доставка новости пользователю из outbox
+type Delivery struct {
+	ID     int64
+	UserID int64
+	News   NewsItem
+	// Attempts — число неудачных попыток до этой
+	Attempts int
+	// Read — пользователь уже видел новость (например, в /latest)
+	Read bool
+}
+
+// Итог доставки из outbox
+const (
+	DeliverySent     = "sent"
+	DeliverySkipped  = "skipped"  // не прошла фильтр или уже прочитана
+	DeliveryDeferred = "deferred" // отложена до дайджеста
+	DeliveryFailed   = "failed"   // исчерпаны попытки
+)
+
+// Weekdays — набор дней недели: бит i соответствует time.Weekday(i)
+type Weekdays uint8
+
+const (
+	EveryDay    Weekdays = 1<<7 - 1
+	WorkingDays Weekdays = EveryDay &^ (1<<time.Saturday | 1<<time.Sunday)
+	WeekendDays Weekdays = 1<<time.Saturday | 1<<time.Sunday
+)
+
+// Минут в сутках: TimeOfDay лежит в [0, minutesPerDay)
+const minutesPerDay = 24 * 60
+
+// WeekdaysOf собирает набор из отдельных дней
+func WeekdaysOf(days ...time.Weekday) Weekdays {
+	var w Weekdays
+	for _, d := range days {
+		w |= 1 << d
+	}
+	return w
+}
+
+// Has — входит ли день в набор
+func (w Weekdays) Has(d time.Weekday) bool {
+	return w&(1<<d) != 0
+}
+
+// AutopostSchedule — время дайджеста пользователя
+type AutopostSchedule struct {
+	UserID int64
+	// TimeOfDay — минуты от полуночи по Timezone
+	TimeOfDay int
+	Weekdays  Weekdays
+	Timezone  string
+	Enabled   bool
+}
+
+// Clock — время дайджеста в виде HH:MM
+func (s AutopostSchedule) Clock() string {
+	return fmt.Sprintf("%02d:%02d", s.TimeOfDay/60, s.TimeOfDay%60)
+}
+
+// Validate проверяет время, дни недели и часовой пояс
+func (s AutopostSchedule) Validate() error {
+	if s.TimeOfDay < 0 || s.TimeOfDay >= minutesPerDay {
+		return fmt.Errorf("время %d вне суток", s.TimeOfDay)
+	}
+	if s.Weekdays == 0 || s.Weekdays > EveryDay {
+		return fmt.Errorf("неверный набор дней недели %b", s.Weekdays)
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return fmt.Errorf("часовой пояс %q: %w", s.Timezone, err)
+	}
+	return nil
+}
+
+// ValidateAutopost проверяет расписание пользователя перед сохранением
+func ValidateAutopost(schedules []AutopostSchedule) error {
+	seen := make(map[int]bool, len(schedules))
+	for _, s := range schedules {
+		if err := s.Validate(); err != nil {
+			return err
+		}
+		if seen[s.TimeOfDay] {
+			return fmt.Errorf("время %s указано дважды", s.Clock())
+		}
+		seen[s.TimeOfDay] = true
+	}
+	return nil
+}
+
+// Due — наступило ли в момент t время дайджеста (с точностью до минуты)
+func (s AutopostSchedule) Due(t time.Time) bool {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return false
+	}
+	t = t.In(loc)
+	return s.Enabled && s.Weekdays.Has(t.Weekday()) && t.Hour()*60+t.Minute() == s.TimeOfDay
+}
+
+// SettingChange — запись истории изменения настройки.
+// OldValue пуст, если до этого настройка не была задана
+type SettingChange struct {
+	Key       string
+	OldValue  string
+	NewValue  string
+	AdminID   int64
+	ChangedAt time.Time
+}
+
+// Статусы пользователя
+const (
+	UserActive = "active"
+	// заблокировал бота
+	UserBlocked = "blocked"
+	// аккаунт удалён в Telegram
+	UserDeactivated = "deactivated"
+)
+
+// Source — RSS-источник с описанием для списков и меню
+type Source struct {
+	URL         string
+	Title       string
+	Description string
+	Category    string
+	Language    string
+	// AddedBy и AddedAt нулевые у источников, добавленных до появления метаданных
+	AddedBy int64
+	AddedAt time.Time
+	// SortOrder — место в списках: больше — выше
+	SortOrder int
+	// Priority — вклад источника в важность новостей
+	Priority int
+}
+
+// UserProfile — пользователь бота с данными из Telegram
+type UserProfile struct {
+	ID           int64
+	Username     string
+	FirstName    string
+	LastName     string
+	LanguageCode string
+	// JoinedAt нулевое, если пользователь пришёл до появления профилей
+	// и дату восстановить не удалось
+	JoinedAt time.Time
+	// LastSeenAt нулевое, если пользователь не появлялся после появления профилей
+	LastSeenAt time.Time
+	Status     string
+}
+
+// Name — имя и фамилия через пробел
+func (p UserProfile) Name() string {
+	return strings.TrimSpace(p.FirstName + " " + p.LastName)
+}
+
+// AuditEntry — действие админа: кто, что, с какими аргументами и чем закончилось
+type AuditEntry struct {
+	ID        int64
+	ActorID   int64
+	Action    string
+	Args      string
+	Result    string
+	CreatedAt time.Time
+}
+
+// AuditFilter — отбор записей журнала; нулевые поля не ограничивают выборку
+type AuditFilter struct {
+	ActorID int64
+	Action  string
+}
+
+// NewsCursor — ключ keyset-пагинации: новости упорядочены по (PubDate, ID)
+type NewsCursor struct {
+	PubDate time.Time
+	ID      int64
+}
+
+// PageDirection — какую страницу выбрать относительно курсора
+type PageDirection int
+
+const (
+	// PageFirst — самые новые, курсор не нужен
+	PageFirst PageDirection = iota
+	// PageAt — начиная с курсора включительно
+	PageAt
+	// PageAfter — следующие за курсором (старше)
+	PageAfter
+	// PageBefore — предшествующие курсору (новее)
+	PageBefore
+	// PageLast — самые старые, курсор не нужен
+	PageLast
+)
+
+// Cursor возвращает ключ пагинации новости
+func (n NewsItem) Cursor() NewsCursor {
+	return NewsCursor{PubDate: n.PubDate, ID: n.ID}
+}
+
+// SearchQuery — запрос /search: слова и необязательные фильтры
+type SearchQuery struct {
+	Text string
+	// Source — подстрока URL источника
+	Source string
+	// From, To — интервал дат публикации [From, To); нулевое значение — без ограничения
+	From time.Time
+	To   time.Time
+}
+
+// RetentionStats — сколько записей удалит (или удалила) очистка
+type RetentionStats struct {
+	News        int
+	ReadMarkers int
+}
+
+// PriceAlert — ценовой алерт пользователя: Ticker Op Level, например SBER > 300
+type PriceAlert struct {
+	ID        int64
+	UserID    int64
+	Ticker    string
+	Op        string
+	Level     float64
+	CreatedAt time.Time
+}
+
+// EventNotification — событие, о котором пора уведомить пользователя
+type EventNotification struct {
+	UserID       int64
+	Event        calendar.Event
+	RemindBefore int
+}
+
+// DayCount — число записей за сутки (UTC); Key — разрез, например источник
+type DayCount struct {
+	Day   time.Time
+	Key   string
+	Count int
+}
@@ -0,0 +1,41 @@
+package summary
+
+// Стоп-слова для русского и английского языков
+var stopwords = toSet(
+	// русский
+	"и", "в", "во", "не", "что", "он", "на", "я", "с", "со", "как", "а", "то", "все", "она",
+	"так", "его", "но", "да", "ты", "к", "у", "же", "вы", "за", "бы", "по", "только", "ее",
+	"мне", "было", "вот", "от", "меня", "еще", "нет", "о", "из", "ему", "теперь", "когда",
+	"даже", "ну", "вдруг", "ли", "если", "уже", "или", "ни", "быть", "был", "него", "до",
+	"вас", "нибудь", "опять", "уж", "вам", "ведь", "там", "потом", "себя", "ничего", "ей",
+	"может", "они", "тут", "где", "есть", "надо", "ней", "для", "мы", "тебя", "их", "чем",
+	"была", "сам", "чтоб", "без", "будто", "чего", "раз", "тоже", "себе", "под", "будет",
+	"ж", "тогда", "кто", "этот", "того", "потому", "этого", "какой", "совсем", "ним",
+	"здесь", "этом", "один", "почти", "мой", "тем", "чтобы", "нее", "сейчас", "были",
+	"куда", "зачем", "всех", "никогда", "можно", "при", "наконец", "два", "об", "другой",
+	"хоть", "после", "над", "больше", "тот", "через", "эти", "нас", "про", "всего", "них",
+	"какая", "много", "разве", "три", "эту", "моя", "впрочем", "хорошо", "свою", "этой",
+	"перед", "иногда", "лучше", "чуть", "том", "нельзя", "такой", "им", "более", "всегда",
+	"конечно", "всю", "между", "это", "также", "который", "которая", "которые", "которых",
+	"года", "году", "заявил", "сообщил", "сообщает", "словам",
+	// english
+	"a", "an", "the", "and", "or", "but", "if", "then", "else", "of", "at", "by", "for",
+	"with", "about", "against", "between", "into", "through", "during", "before", "after",
+	"above", "below", "to", "from", "up", "down", "in", "out", "on", "off", "over", "under",
+	"again", "further", "once", "here", "there", "when", "where", "why", "how", "all",
+	"any", "both", "each", "few", "more", "most", "other", "some", "such", "no", "nor",
+	"not", "only", "own", "same", "so", "than", "too", "very", "can", "will", "just",
+	"should", "now", "is", "are", "was", "were", "be", "been", "being", "have", "has",
+	"had", "having", "do", "does", "did", "doing", "i", "me", "my", "we", "our", "you",
+	"your", "he", "him", "his", "she", "her", "it", "its", "they", "them", "their",
+	"what", "which", "who", "whom", "this", "that", "these", "those", "am", "would",
+	"could", "also", "said", "says", "according", "year",
+)
+
+func toSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
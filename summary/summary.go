@@ -0,0 +1,158 @@
+package summary
+
+import (
+	"html"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	damping    = 0.85
+	iterations = 30
+	// MaxLen ограничивает длину итогового саммари в символах
+	MaxLen = 400
+)
+
+var (
+	tagRe      = regexp.MustCompile(`(?s)<[^>]*>`)
+	spaceRe    = regexp.MustCompile(`\s+`)
+	sentenceRe = regexp.MustCompile(`[^.!?…]+[.!?…]*`)
+	// пробел, оставшийся от тега перед знаком препинания: «<b>вырос</b>.»
+	punctRe = regexp.MustCompile(`\s+([.,!?…:;])`)
+)
+
+// Summarize выбирает n самых значимых предложений из текста (TextRank)
+// и возвращает их в исходном порядке
+func Summarize(text string, n int) string {
+	text = Clean(text)
+	if text == "" || n <= 0 {
+		return ""
+	}
+
+	sentences := splitSentences(text)
+	if len(sentences) <= n {
+		return truncate(strings.Join(sentences, " "))
+	}
+
+	words := make([]map[string]bool, len(sentences))
+	for i, s := range sentences {
+		words[i] = tokenize(s)
+	}
+
+	// матрица сходства предложений
+	size := len(sentences)
+	weights := make([][]float64, size)
+	for i := range weights {
+		weights[i] = make([]float64, size)
+	}
+	for i := 0; i < size; i++ {
+		for j := i + 1; j < size; j++ {
+			w := similarity(words[i], words[j])
+			weights[i][j] = w
+			weights[j][i] = w
+		}
+	}
+
+	outSum := make([]float64, size)
+	for i := range weights {
+		for _, w := range weights[i] {
+			outSum[i] += w
+		}
+	}
+
+	scores := make([]float64, size)
+	for i := range scores {
+		scores[i] = 1
+	}
+	for it := 0; it < iterations; it++ {
+		next := make([]float64, size)
+		for i := 0; i < size; i++ {
+			var rank float64
+			for j := 0; j < size; j++ {
+				if weights[j][i] == 0 || outSum[j] == 0 {
+					continue
+				}
+				rank += weights[j][i] / outSum[j] * scores[j]
+			}
+			next[i] = (1 - damping) + damping*rank
+		}
+		scores = next
+	}
+
+	idx := make([]int, size)
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		return scores[idx[a]] > scores[idx[b]]
+	})
+	top := idx[:n]
+	sort.Ints(top)
+
+	picked := make([]string, 0, n)
+	for _, i := range top {
+		picked = append(picked, sentences[i])
+	}
+	return truncate(strings.Join(picked, " "))
+}
+
+// Clean убирает HTML-разметку и лишние пробелы
+func Clean(text string) string {
+	text = tagRe.ReplaceAllString(text, " ")
+	text = html.UnescapeString(text)
+	text = spaceRe.ReplaceAllString(text, " ")
+	return strings.TrimSpace(punctRe.ReplaceAllString(text, "$1"))
+}
+
+func splitSentences(text string) []string {
+	var out []string
+	for _, s := range sentenceRe.FindAllString(text, -1) {
+		s = strings.TrimSpace(s)
+		if len([]rune(s)) < 3 {
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func tokenize(s string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(w)) < 3 || stopwords[w] {
+			continue
+		}
+		words[w] = true
+	}
+	return words
+}
+
+// similarity — мера сходства из оригинальной статьи TextRank
+func similarity(a, b map[string]bool) float64 {
+	if len(a) < 2 || len(b) < 2 {
+		return 0
+	}
+	common := 0
+	for w := range a {
+		if b[w] {
+			common++
+		}
+	}
+	if common == 0 {
+		return 0
+	}
+	return float64(common) / (math.Log(float64(len(a))) + math.Log(float64(len(b))))
+}
+
+func truncate(s string) string {
+	r := []rune(s)
+	if len(r) <= MaxLen {
+		return s
+	}
+	return strings.TrimSpace(string(r[:MaxLen-1])) + "…"
+}
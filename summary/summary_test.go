@@ -0,0 +1,75 @@
+package summary
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"<p>Сбербанк <b>повысил</b> дивиденды</p>", "Сбербанк повысил дивиденды"},
+		{"Цена &gt; 300 &amp; растёт", "Цена > 300 & растёт"},
+		{"<img src=\"x.png\"/>\n\n  Текст\tс   пробелами  ", "Текст с пробелами"},
+		{"<a\nhref=\"https://example.com\">ссылка</a>", "ссылка"},
+		{"<br><br/>", ""},
+		{"Рынок <b>вырос</b>, а <i>затем</i> упал !", "Рынок вырос, а затем упал!"},
+	}
+	for _, tt := range tests {
+		if got := Clean(tt.in); got != tt.want {
+			t.Errorf("Clean(%q) = %q, ожидалось %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	article := "Сбербанк отчитался о рекордной прибыли за квартал. " +
+		"Прибыль Сбербанка выросла на двадцать процентов за квартал. " +
+		"Погода в Москве была солнечной. " +
+		"Аналитики ждут роста дивидендов Сбербанка после рекордной прибыли."
+	long := strings.Repeat("Очень длинное предложение про рынок акций и облигаций. ", 20)
+
+	tests := []struct {
+		name string
+		text string
+		n    int
+		want string
+	}{
+		{"пустой текст", "", 2, ""},
+		{"только разметка", "<p></p>", 2, ""},
+		{"n = 0", article, 0, ""},
+		{"n < 0", article, -1, ""},
+		{"предложений меньше n", "Первое предложение. Второе!", 3, "Первое предложение. Второе!"},
+		{"короткие обрывки отбрасываются", "А. Б. Рынок вырос.", 5, "Рынок вырос."},
+		{"разметка убирается", "<p>Рынок <b>вырос</b>.</p>", 1, "Рынок вырос."},
+		{"лишнее предложение не выбирается", article, 3,
+			"Сбербанк отчитался о рекордной прибыли за квартал. " +
+				"Прибыль Сбербанка выросла на двадцать процентов за квартал. " +
+				"Аналитики ждут роста дивидендов Сбербанка после рекордной прибыли."},
+		{"обрезка по MaxLen", long, 30, strings.TrimSpace(string([]rune(long)[:MaxLen-1])) + "…"},
+	}
+	for _, tt := range tests {
+		got := Summarize(tt.text, tt.n)
+		if got != tt.want {
+			t.Errorf("%s: Summarize = %q, ожидалось %q", tt.name, got, tt.want)
+		}
+		if n := utf8.RuneCountInString(got); n > MaxLen {
+			t.Errorf("%s: длина %d больше MaxLen", tt.name, n)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	exact := strings.Repeat("я", MaxLen)
+	if got := truncate(exact); got != exact {
+		t.Errorf("строка ровно MaxLen обрезана: %d символов", utf8.RuneCountInString(got))
+	}
+	over := strings.Repeat("я", MaxLen-2) + "  ab"
+	got := truncate(over)
+	if want := strings.Repeat("я", MaxLen-2) + "…"; got != want {
+		t.Errorf("truncate: %q, ожидалось %q", got, want)
+	}
+}
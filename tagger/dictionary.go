@@ -0,0 +1,71 @@
+package tagger
+
+// Тикеры, которые распознаются, когда написаны заглавными буквами
+var defaultTickers = []string{
+	// Мосбиржа
+	"SBER", "SBERP", "GAZP", "LKOH", "GMKN", "NVTK", "ROSN", "TATN", "TATNP", "YDEX",
+	"MGNT", "MTSS", "ALRS", "CHMF", "NLMK", "MAGN", "PLZL", "VTBR", "AFLT", "MOEX",
+	"SNGS", "SNGSP", "PHOR", "RUAL", "IRAO", "HYDR", "OZON", "PIKK", "SIBN",
+	"TRNFP", "AFKS", "FEES", "RTKM", "SMLT", "POSI", "X5",
+	// криптовалюты
+	"BTC", "ETH", "USDT", "BNB", "SOL", "XRP", "TON", "DOGE", "ADA", "TRX",
+}
+
+// Встроенные алиасы компаний и активов (RU и EN)
+var defaultAliases = map[string]string{
+	"сбербанк":          "SBER",
+	"сбер":              "SBER",
+	"sberbank":          "SBER",
+	"газпром":           "GAZP",
+	"gazprom":           "GAZP",
+	"газпром нефть":     "SIBN",
+	"газпромнефть":      "SIBN",
+	"лукойл":            "LKOH",
+	"lukoil":            "LKOH",
+	"норникель":         "GMKN",
+	"норильский никель": "GMKN",
+	"nornickel":         "GMKN",
+	"новатэк":           "NVTK",
+	"novatek":           "NVTK",
+	"роснефть":          "ROSN",
+	"rosneft":           "ROSN",
+	"татнефть":          "TATN",
+	"яндекс":            "YDEX",
+	"yandex":            "YDEX",
+	"магнит":            "MGNT",
+	"мтс":               "MTSS",
+	"алроса":            "ALRS",
+	"северсталь":        "CHMF",
+	"нлмк":              "NLMK",
+	"ммк":               "MAGN",
+	"полюс":             "PLZL",
+	"втб":               "VTBR",
+	"аэрофлот":          "AFLT",
+	"aeroflot":          "AFLT",
+	"мосбиржа":          "MOEX",
+	"московская биржа":  "MOEX",
+	"сургутнефтегаз":    "SNGS",
+	"фосагро":           "PHOR",
+	"русал":             "RUAL",
+	"интер рао":         "IRAO",
+	"русгидро":          "HYDR",
+	"транснефть":        "TRNFP",
+	"ростелеком":        "RTKM",
+	"самолет":           "SMLT",
+	"самолёт":           "SMLT",
+	"озон":              "OZON",
+	"apple":             "AAPL",
+	"microsoft":         "MSFT",
+	"tesla":             "TSLA",
+	"nvidia":            "NVDA",
+	"amazon":            "AMZN",
+	"alphabet":          "GOOGL",
+	"google":            "GOOGL",
+	"meta platforms":    "META",
+	"bitcoin":           "BTC",
+	"биткоин":           "BTC",
+	"биткойн":           "BTC",
+	"ethereum":          "ETH",
+	"эфириум":           "ETH",
+	"toncoin":           "TON",
+}
@@ -0,0 +1,164 @@
+package tagger
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Падежные окончания, с которыми слово ещё считается формой алиаса:
+// сбербанк → сбербанка, сбербанком; алроса → алросы; норникель → норникеля.
+// Другие слова с тем же началом («магнитный», «полюсный») не совпадают
+var (
+	hardEndings = []string{"а", "у", "ом", "е", "ы", "ов", "ам", "ами", "ах"}
+	// алиасы на -а: окончание заменяется
+	femEndings = []string{"а", "ы", "и", "е", "у", "ой", "ою"}
+	// алиасы на -ь: окончание заменяется
+	softEndings = []string{"ь", "я", "ю", "ем", "е", "и", "ью", "ей"}
+)
+
+var cashtagRe = regexp.MustCompile(`\$([A-Za-z][A-Za-z0-9]{0,5})\b`)
+
+// Tagger распознаёт упоминания инструментов в тексте новостей
+type Tagger struct {
+	tickers map[string]bool
+	aliases []alias
+}
+
+type alias struct {
+	words  []string
+	ticker string
+}
+
+// New создаёт теггер со встроенным словарём и алиасами администратора
+// (алиас → тикер)
+func New(custom map[string]string) *Tagger {
+	t := &Tagger{tickers: make(map[string]bool)}
+	for _, tk := range defaultTickers {
+		t.tickers[tk] = true
+	}
+	for a, tk := range defaultAliases {
+		t.add(a, tk)
+	}
+	for a, tk := range custom {
+		t.add(a, tk)
+	}
+	// длинные алиасы проверяются первыми, см. Tag
+	sort.SliceStable(t.aliases, func(i, j int) bool {
+		return len(t.aliases[i].words) > len(t.aliases[j].words)
+	})
+	return t
+}
+
+func (t *Tagger) add(name, ticker string) {
+	ticker = NormalizeTicker(ticker)
+	words := splitWords(strings.ToLower(name))
+	if ticker == "" || len(words) == 0 {
+		return
+	}
+	t.tickers[ticker] = true
+	t.aliases = append(t.aliases, alias{words: words, ticker: ticker})
+}
+
+// Tag возвращает отсортированный список тикеров, упомянутых в текстах
+func (t *Tagger) Tag(texts ...string) []string {
+	found := make(map[string]bool)
+	for _, text := range texts {
+		// кэштеги принимаются всегда: $AAPL
+		for _, m := range cashtagRe.FindAllStringSubmatch(text, -1) {
+			found[strings.ToUpper(m[1])] = true
+		}
+
+		// тикеры, написанные заглавными: SBER, BTC
+		words := splitWords(text)
+		for _, w := range words {
+			if w == strings.ToUpper(w) && t.tickers[w] {
+				found[w] = true
+			}
+		}
+
+		// названия компаний и прочие алиасы. Слова, занятые более длинным
+		// алиасом, короткие уже не берут: «Газпром нефть» — SIBN, но не GAZP
+		lower := make([]string, len(words))
+		for i, w := range words {
+			lower[i] = strings.ToLower(w)
+		}
+		taken := make([]int, len(lower))
+		for _, a := range t.aliases {
+			for i := 0; i+len(a.words) <= len(lower); i++ {
+				if !aliasAt(lower[i:], taken[i:], a.words) {
+					continue
+				}
+				found[a.ticker] = true
+				for j := range a.words {
+					taken[i+j] = len(a.words)
+				}
+			}
+		}
+	}
+
+	tags := make([]string, 0, len(found))
+	for tk := range found {
+		tags = append(tags, tk)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// NormalizeTicker приводит тикер к виду, пригодному для хештега
+func NormalizeTicker(s string) string {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "$")
+	s = strings.TrimPrefix(s, "#")
+	return strings.ToUpper(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return -1
+	}, s))
+}
+
+// Hashtags форматирует теги как хештеги Telegram
+func Hashtags(tags []string) string {
+	out := make([]string, 0, len(tags))
+	for _, tk := range tags {
+		out = append(out, "#"+tk)
+	}
+	return strings.Join(out, " ")
+}
+
+func splitWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// aliasAt проверяет, начинается ли words с алиаса; taken — длина алиаса,
+// уже занявшего слово, или 0
+func aliasAt(words []string, taken []int, alias []string) bool {
+	for j, aw := range alias {
+		if taken[j] > len(alias) || !wordMatches(words[j], aw) {
+			return false
+		}
+	}
+	return true
+}
+
+func wordMatches(word, alias string) bool {
+	if word == alias {
+		return true
+	}
+	// короткие алиасы сравниваем только целиком
+	if len([]rune(alias)) < 4 {
+		return false
+	}
+	stem, endings := alias, hardEndings
+	if s, ok := strings.CutSuffix(alias, "а"); ok {
+		stem, endings = s, femEndings
+	} else if s, ok := strings.CutSuffix(alias, "ь"); ok {
+		stem, endings = s, softEndings
+	}
+	ending, ok := strings.CutPrefix(word, stem)
+	return ok && slices.Contains(endings, ending)
+}
@@ -0,0 +1,59 @@
+package tagger
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTag(t *testing.T) {
+	tg := New(map[string]string{"тинькофф": "T"})
+	tests := []struct {
+		text string
+		want []string
+	}{
+		// формы названий
+		{"Сбербанк повысил дивиденды", []string{"SBER"}},
+		{"Акции Сбербанка выросли", []string{"SBER"}},
+		{"Сделка со Сбербанком закрыта", []string{"SBER"}},
+		{"Выручка Магнита выросла", []string{"MGNT"}},
+		{"Добыча Алросы снизилась", []string{"ALRS"}},
+		{"Дивиденды Норникеля", []string{"GMKN"}},
+		{"Отчёт Газпромнефти", []string{"SIBN"}},
+		{"Клиенты Тинькоффа", []string{"T"}},
+		// другие слова с тем же началом
+		{"Магнитный шторм ожидается в среду", []string{}},
+		{"Полюсный переключатель", []string{}},
+		{"Сберегательные сертификаты", []string{}},
+		// длинный алиас важнее короткого внутри него
+		{"Газпром нефть отчиталась", []string{"SIBN"}},
+		{"Газпром нефть и Газпром", []string{"GAZP", "SIBN"}},
+		{"Норильский никель", []string{"GMKN"}},
+		// тикеры, кэштеги и короткие алиасы
+		{"SBER и GAZP на максимумах", []string{"GAZP", "SBER"}},
+		{"sber в нижнем регистре", []string{}},
+		{"Покупаю $aapl и $TSLA", []string{"AAPL", "TSLA"}},
+		{"МТС запускает сервис", []string{"MTSS"}},
+		{"мтсы", []string{}},
+		{"", []string{}},
+	}
+	for _, tt := range tests {
+		if got := tg.Tag(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tag(%q) = %v, ожидалось %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeTicker(t *testing.T) {
+	tests := map[string]string{
+		"sber":   "SBER",
+		" $gazp": "GAZP",
+		"#BTC":   "BTC",
+		"x-5":    "X5",
+		"!!!":    "",
+	}
+	for in, want := range tests {
+		if got := NormalizeTicker(in); got != want {
+			t.Errorf("NormalizeTicker(%q) = %q, ожидалось %q", in, got, want)
+		}
+	}
+}